@@ -7,64 +7,175 @@ import (
 	"stocks/currency"
 	"stocks/separator"
 	"stocks/stock"
+	"strings"
+	"time"
 )
 
 type (
 	Repository interface {
-		Assets(ctx context.Context) (Assets, error)
+		Assets(ctx context.Context, portfolio string) (Assets, error)
+		AssetsAt(ctx context.Context, portfolio string, date time.Time) (Assets, error)
 	}
 
 	Asset struct {
 		Symbol       stock.Symbol
+		Class        stock.Class
 		Quantity     int
 		AveragePrice currency.Currency
+		Cost         currency.Currency
 		LastPrice    currency.Currency
+		Stale        bool
+		QuoteError   error
 		Investment   currency.Currency
 		Settled      currency.Currency
+		Realized     currency.Currency
+		DayTrade     currency.Currency
+		Income       currency.Currency
 	}
 
 	Assets []Asset
 )
 
 func (a Asset) Balance() currency.Currency {
-	balance := a.Settled.Float64() - a.Investment.Float64()
-	return currency.NewFromFloat(balance)
+	return a.Settled.Sub(a.Investment)
+}
+
+func (a Asset) Unrealized() currency.Currency {
+	return a.LastPrice.Mul(a.Quantity).Sub(a.Cost)
 }
 
 func (a Asset) GainLoss() currency.Currency {
-	balance := float64(a.Quantity) * a.LastPrice.Float64()
-	return currency.NewFromFloat(balance + a.Balance().Float64())
+	return currency.Sum(a.Realized, a.Unrealized(), a.DayTrade, a.Income)
+}
+
+func (a Asset) YieldOnCost() float64 {
+	cost := a.Cost
+	if !cost.IsPositive() {
+		return 0
+	}
+
+	return float64(a.Income.Cents()) / float64(cost.Cents())
+}
+
+func (a Asset) Priced() bool {
+	return a.Quantity == 0 || a.QuoteError == nil
+}
+
+func (a Asset) lastPrice() string {
+	if !a.Priced() {
+		return "n/a"
+	}
+
+	if a.Stale {
+		return fmt.Sprintf("%s (stale)", a.LastPrice)
+	}
+
+	return a.LastPrice.String()
+}
+
+func (a Asset) unrealized() string {
+	if !a.Priced() {
+		return "n/a"
+	}
+
+	return a.Unrealized().String()
+}
+
+func (a Asset) gainLoss() string {
+	if !a.Priced() {
+		return "n/a"
+	}
+
+	return a.GainLoss().String()
 }
 
 func (a Assets) Balance() currency.Currency {
-	balance := 0.0
+	var balance currency.Currency
+
+	for _, asset := range a {
+		balance = balance.Add(asset.Balance())
+	}
+
+	return balance
+}
+
+func (a Assets) Realized() currency.Currency {
+	var realized currency.Currency
 
 	for _, asset := range a {
-		balance += asset.Balance().Float64()
+		realized = realized.Add(asset.Realized)
 	}
 
-	return currency.NewFromFloat(balance)
+	return realized
+}
+
+func (a Assets) Unrealized() currency.Currency {
+	var unrealized currency.Currency
+
+	for _, asset := range a {
+		if asset.Priced() {
+			unrealized = unrealized.Add(asset.Unrealized())
+		}
+	}
+
+	return unrealized
+}
+
+func (a Assets) DayTrade() currency.Currency {
+	var dayTrade currency.Currency
+
+	for _, asset := range a {
+		dayTrade = dayTrade.Add(asset.DayTrade)
+	}
+
+	return dayTrade
+}
+
+func (a Assets) Income() currency.Currency {
+	var income currency.Currency
+
+	for _, asset := range a {
+		income = income.Add(asset.Income)
+	}
+
+	return income
 }
 
 func (a Assets) GainLoss() currency.Currency {
-	gainLoss := 0.0
+	var gainLoss currency.Currency
 
 	for _, asset := range a {
-		gainLoss += asset.GainLoss().Float64()
+		if asset.Priced() {
+			gainLoss = gainLoss.Add(asset.GainLoss())
+		}
 	}
 
-	return currency.NewFromFloat(gainLoss)
+	return gainLoss
+}
+
+func (a Assets) Unpriced() Assets {
+	var output Assets
+
+	for _, asset := range a {
+		if !asset.Priced() {
+			output = append(output, asset)
+		}
+	}
+
+	return output
 }
 
 func (a Assets) Print(writer io.Writer, sep separator.Separator) error {
-	title := fmt.Sprintf("Symbol%sQtd.%sAvg. Price%sLast Price%sGain/Loss\n", sep, sep, sep, sep)
+	title := fmt.Sprintf("Symbol%sClass%sQtd.%sAvg. Price%sLast Price%sRealized%sUnrealized%sDay Trade%sIncome%sYoC%sGain/Loss\n",
+		sep, sep, sep, sep, sep, sep, sep, sep, sep, sep)
 	if _, err := io.WriteString(writer, title); err != nil {
 		return err
 	}
 
 	for _, asset := range a {
-		line := fmt.Sprintf("%s%s%d%s%s%s%s%s%s\n",
-			asset.Symbol, sep, asset.Quantity, sep, asset.AveragePrice, sep, asset.LastPrice, sep, asset.GainLoss())
+		line := fmt.Sprintf("%s%s%s%s%d%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s\n",
+			asset.Symbol, sep, asset.Class, sep, asset.Quantity, sep, asset.AveragePrice, sep, asset.lastPrice(), sep, asset.Realized, sep,
+			asset.unrealized(), sep, asset.DayTrade, sep, asset.Income, sep, percent(asset.YieldOnCost()), sep, asset.gainLoss())
 
 		if _, err := io.WriteString(writer, line); err != nil {
 			return err
@@ -73,3 +184,32 @@ func (a Assets) Print(writer io.Writer, sep separator.Separator) error {
 
 	return nil
 }
+
+func (a Assets) PrintUnpriced(writer io.Writer) error {
+	unpriced := a.Unpriced()
+	if len(unpriced) == 0 {
+		return nil
+	}
+
+	symbols := make([]string, len(unpriced))
+	for i, asset := range unpriced {
+		symbols[i] = string(asset.Symbol)
+	}
+
+	summary := fmt.Sprintf("Gain/Loss excludes %s: quote unavailable\n", strings.Join(symbols, ", "))
+	if _, err := io.WriteString(writer, summary); err != nil {
+		return err
+	}
+
+	for _, asset := range unpriced {
+		if _, err := fmt.Fprintf(writer, "%s: %v\n", asset.Symbol, asset.QuoteError); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func percent(value float64) string {
+	return strings.ReplaceAll(fmt.Sprintf("%.2f%%", value*100), ".", ",")
+}
@@ -2,10 +2,10 @@ package asset
 
 import (
 	"bytes"
+	"errors"
 	"reflect"
 	"stocks/currency"
 	"stocks/separator"
-	"stocks/stock"
 	"testing"
 )
 
@@ -57,14 +57,70 @@ func TestAsset_Balance(t *testing.T) {
 	}
 }
 
+func TestAsset_Unrealized(t *testing.T) {
+	type fields struct {
+		Quantity     int
+		AveragePrice currency.Currency
+		Cost         currency.Currency
+		LastPrice    currency.Currency
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   currency.Currency
+	}{
+		{
+			name: "Should calculate unrealized gain on open quantity",
+			fields: fields{
+				Quantity:     10,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(100),
+				LastPrice:    currency.NewFromFloat(12),
+			},
+			want: currency.NewFromFloat(20),
+		},
+		{
+			name: "Should calculate unrealized loss on open quantity",
+			fields: fields{
+				Quantity:     10,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(100),
+				LastPrice:    currency.NewFromFloat(8),
+			},
+			want: currency.NewFromFloat(-20),
+		},
+		{
+			name: "Should return zero for closed positions",
+			fields: fields{
+				LastPrice: currency.NewFromFloat(12),
+			},
+			want: currency.NewFromFloat(0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := Asset{
+				Quantity:     tt.fields.Quantity,
+				AveragePrice: tt.fields.AveragePrice,
+				Cost:         tt.fields.Cost,
+				LastPrice:    tt.fields.LastPrice,
+			}
+			if got := a.Unrealized(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unrealized() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestAsset_GainLoss(t *testing.T) {
 	type fields struct {
-		Symbol       stock.Symbol
 		Quantity     int
 		AveragePrice currency.Currency
+		Cost         currency.Currency
 		LastPrice    currency.Currency
-		Investment   currency.Currency
-		Settled      currency.Currency
+		Realized     currency.Currency
+		DayTrade     currency.Currency
+		Income       currency.Currency
 	}
 	tests := []struct {
 		name   string
@@ -74,64 +130,78 @@ func TestAsset_GainLoss(t *testing.T) {
 		{
 			name: "Should calculate gain for stocks valuation using current value",
 			fields: fields{
-				Quantity:   10,
-				LastPrice:  currency.NewFromFloat(12),
-				Investment: currency.NewFromFloat(100),
+				Quantity:     10,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(100),
+				LastPrice:    currency.NewFromFloat(12),
 			},
 			want: currency.NewFromFloat(20),
 		},
 		{
 			name: "Should calculate gain for stocks in sell operations",
 			fields: fields{
-				Investment: currency.NewFromFloat(100),
-				Settled:    currency.NewFromFloat(120),
+				Realized: currency.NewFromFloat(20),
 			},
 			want: currency.NewFromFloat(20),
 		},
 		{
 			name: "Should calculate gain for stocks using current valuation and sell operations",
 			fields: fields{
-				Quantity:   5,
-				LastPrice:  currency.NewFromFloat(12),
-				Investment: currency.NewFromFloat(100),
-				Settled:    currency.NewFromFloat(60),
+				Quantity:     5,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(50),
+				LastPrice:    currency.NewFromFloat(12),
+				Realized:     currency.NewFromFloat(10),
 			},
 			want: currency.NewFromFloat(20),
 		},
 		{
-			name: "Should calculate loss for stocks devaluation",
+			name: "Should calculate gain for stocks including day trade result",
 			fields: fields{
-				Quantity:   10,
-				LastPrice:  currency.NewFromFloat(8),
-				Investment: currency.NewFromFloat(100),
+				Quantity:     10,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(100),
+				LastPrice:    currency.NewFromFloat(12),
+				DayTrade:     currency.NewFromFloat(15),
 			},
-			want: currency.NewFromFloat(-20),
+			want: currency.NewFromFloat(35),
 		},
 		{
-			name: "Should calculate loss for stocks in sell operations",
+			name: "Should calculate gain for stocks including income",
 			fields: fields{
-				Investment: currency.NewFromFloat(100),
-				Settled:    currency.NewFromFloat(80),
+				Quantity:     10,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(100),
+				LastPrice:    currency.NewFromFloat(12),
+				Income:       currency.NewFromFloat(5),
+			},
+			want: currency.NewFromFloat(25),
+		},
+		{
+			name: "Should calculate loss for stocks devaluation",
+			fields: fields{
+				Quantity:     10,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(100),
+				LastPrice:    currency.NewFromFloat(8),
 			},
 			want: currency.NewFromFloat(-20),
 		},
 		{
-			name: "Should calculate loss for stocks using current valuation and sell operations",
+			name: "Should calculate loss for stocks in sell operations",
 			fields: fields{
-				Quantity:   5,
-				LastPrice:  currency.NewFromFloat(8),
-				Investment: currency.NewFromFloat(100),
-				Settled:    currency.NewFromFloat(40),
+				Realized: currency.NewFromFloat(-20),
 			},
 			want: currency.NewFromFloat(-20),
 		},
 		{
-			name: "Should calculate loss for stocks using current valuation and sell operations",
+			name: "Should offset realized gain with unrealized loss",
 			fields: fields{
-				Quantity:   5,
-				LastPrice:  currency.NewFromFloat(8),
-				Investment: currency.NewFromFloat(120),
-				Settled:    currency.NewFromFloat(80),
+				Quantity:     5,
+				AveragePrice: currency.NewFromFloat(12),
+				Cost:         currency.NewFromFloat(60),
+				LastPrice:    currency.NewFromFloat(8),
+				Realized:     currency.NewFromFloat(20),
 			},
 			want: currency.NewFromFloat(0),
 		},
@@ -139,10 +209,13 @@ func TestAsset_GainLoss(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := Asset{
-				Quantity:   tt.fields.Quantity,
-				LastPrice:  tt.fields.LastPrice,
-				Investment: tt.fields.Investment,
-				Settled:    tt.fields.Settled,
+				Quantity:     tt.fields.Quantity,
+				AveragePrice: tt.fields.AveragePrice,
+				Cost:         tt.fields.Cost,
+				LastPrice:    tt.fields.LastPrice,
+				Realized:     tt.fields.Realized,
+				DayTrade:     tt.fields.DayTrade,
+				Income:       tt.fields.Income,
 			}
 			if got := s.GainLoss(); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("GainLoss() = %v, want %v", got, tt.want)
@@ -151,6 +224,51 @@ func TestAsset_GainLoss(t *testing.T) {
 	}
 }
 
+func TestAsset_YieldOnCost(t *testing.T) {
+	type fields struct {
+		Quantity     int
+		AveragePrice currency.Currency
+		Cost         currency.Currency
+		Income       currency.Currency
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   float64
+	}{
+		{
+			name: "Should calculate yield on cost properly",
+			fields: fields{
+				Quantity:     10,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(100),
+				Income:       currency.NewFromFloat(5),
+			},
+			want: 0.05,
+		},
+		{
+			name: "Should return zero yield on cost for closed positions",
+			fields: fields{
+				Income: currency.NewFromFloat(5),
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := Asset{
+				Quantity:     tt.fields.Quantity,
+				AveragePrice: tt.fields.AveragePrice,
+				Cost:         tt.fields.Cost,
+				Income:       tt.fields.Income,
+			}
+			if got := a.YieldOnCost(); got != tt.want {
+				t.Errorf("YieldOnCost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestAssets_Balance(t *testing.T) {
 	type fields struct {
 		assets Assets
@@ -204,24 +322,58 @@ func TestAssets_GainLoss(t *testing.T) {
 			fields: fields{
 				assets: Assets{
 					{
-						Quantity:   10,
-						LastPrice:  currency.NewFromFloat(12),
-						Investment: currency.NewFromFloat(100),
+						Quantity:     10,
+						AveragePrice: currency.NewFromFloat(10),
+						Cost:         currency.NewFromFloat(100),
+						LastPrice:    currency.NewFromFloat(12),
 					},
 					{
-						Investment: currency.NewFromFloat(100),
-						Settled:    currency.NewFromFloat(120),
+						Realized: currency.NewFromFloat(20),
 					},
 					{
-						Quantity:   5,
-						LastPrice:  currency.NewFromFloat(12),
-						Investment: currency.NewFromFloat(100),
-						Settled:    currency.NewFromFloat(60),
+						Quantity:     5,
+						AveragePrice: currency.NewFromFloat(10),
+						Cost:         currency.NewFromFloat(50),
+						LastPrice:    currency.NewFromFloat(12),
+						Realized:     currency.NewFromFloat(10),
 					},
 				},
 			},
 			want: currency.NewFromFloat(60),
 		},
+		{
+			name: "Should exclude assets without quote from gain / loss",
+			fields: fields{
+				assets: Assets{
+					{
+						Quantity:     10,
+						AveragePrice: currency.NewFromFloat(10),
+						Cost:         currency.NewFromFloat(100),
+						LastPrice:    currency.NewFromFloat(12),
+					},
+					{
+						Quantity:     5,
+						AveragePrice: currency.NewFromFloat(20),
+						Cost:         currency.NewFromFloat(100),
+						QuoteError:   errors.New("offline"),
+					},
+				},
+			},
+			want: currency.NewFromFloat(20),
+		},
+		{
+			name: "Should keep closed positions in gain / loss without quote",
+			fields: fields{
+				assets: Assets{
+					{
+						Realized:   currency.NewFromFloat(20),
+						Income:     currency.NewFromFloat(5),
+						QuoteError: errors.New("offline"),
+					},
+				},
+			},
+			want: currency.NewFromFloat(25),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -255,24 +407,73 @@ func TestAssets_Print(t *testing.T) {
 						Symbol:       "STOCK1",
 						Quantity:     8,
 						AveragePrice: currency.NewFromFloat(10),
+						Cost:         currency.NewFromFloat(80),
 						LastPrice:    currency.NewFromFloat(12),
 						Investment:   currency.NewFromFloat(100),
 						Settled:      currency.NewFromFloat(0),
+						Realized:     currency.NewFromFloat(-20),
 					},
 					{
 						Symbol:       "STOCK2",
 						Quantity:     6,
 						AveragePrice: currency.NewFromFloat(9.8),
+						Cost:         currency.NewFromFloat(58.8),
 						LastPrice:    currency.NewFromFloat(21),
 						Investment:   currency.NewFromFloat(220),
 						Settled:      currency.NewFromFloat(160),
+						Realized:     currency.NewFromFloat(-1.2),
+						DayTrade:     currency.NewFromFloat(10),
+						Income:       currency.NewFromFloat(12),
+					},
+				},
+			},
+			args: args{
+				sep: separator.Comma,
+			},
+			wantWriter: "Symbol,Class,Qtd.,Avg. Price,Last Price,Realized,Unrealized,Day Trade,Income,YoC,Gain/Loss\nSTOCK1,STOCK,8,R$ 10,00,R$ 12,00,-(R$ 20,00),R$ 16,00,R$ 0,00,R$ 0,00,0,00%,-(R$ 4,00)\nSTOCK2,STOCK,6,R$ 9,80,R$ 21,00,-(R$ 1,20),R$ 67,20,R$ 10,00,R$ 12,00,20,41%,R$ 88,00\n",
+			wantErr:    false,
+		},
+		{
+			name: "Should flag stale last prices",
+			fields: fields{
+				assets: Assets{
+					{
+						Symbol:       "STOCK1",
+						Quantity:     8,
+						AveragePrice: currency.NewFromFloat(10),
+						Cost:         currency.NewFromFloat(80),
+						LastPrice:    currency.NewFromFloat(12),
+						Stale:        true,
+						Investment:   currency.NewFromFloat(100),
+						Settled:      currency.NewFromFloat(0),
+						Realized:     currency.NewFromFloat(-20),
+					},
+				},
+			},
+			args: args{
+				sep: separator.Comma,
+			},
+			wantWriter: "Symbol,Class,Qtd.,Avg. Price,Last Price,Realized,Unrealized,Day Trade,Income,YoC,Gain/Loss\nSTOCK1,STOCK,8,R$ 10,00,R$ 12,00 (stale),-(R$ 20,00),R$ 16,00,R$ 0,00,R$ 0,00,0,00%,-(R$ 4,00)\n",
+			wantErr:    false,
+		},
+		{
+			name: "Should mark assets without quote",
+			fields: fields{
+				assets: Assets{
+					{
+						Symbol:       "STOCK1",
+						Quantity:     8,
+						AveragePrice: currency.NewFromFloat(10),
+						Cost:         currency.NewFromFloat(80),
+						Investment:   currency.NewFromFloat(80),
+						QuoteError:   errors.New("offline"),
 					},
 				},
 			},
 			args: args{
 				sep: separator.Comma,
 			},
-			wantWriter: "Symbol,Qtd.,Avg. Price,Last Price,Gain/Loss\nSTOCK1,8,R$ 10,00,R$ 12,00,-(R$ 4,00)\nSTOCK2,6,R$ 9,80,R$ 21,00,R$ 66,00\n",
+			wantWriter: "Symbol,Class,Qtd.,Avg. Price,Last Price,Realized,Unrealized,Day Trade,Income,YoC,Gain/Loss\nSTOCK1,STOCK,8,R$ 10,00,n/a,R$ 0,00,n/a,R$ 0,00,R$ 0,00,0,00%,n/a\n",
 			wantErr:    false,
 		},
 		{
@@ -283,24 +484,30 @@ func TestAssets_Print(t *testing.T) {
 						Symbol:       "STOCK1",
 						Quantity:     8,
 						AveragePrice: currency.NewFromFloat(10),
+						Cost:         currency.NewFromFloat(80),
 						LastPrice:    currency.NewFromFloat(12),
 						Investment:   currency.NewFromFloat(100),
 						Settled:      currency.NewFromFloat(0),
+						Realized:     currency.NewFromFloat(-20),
 					},
 					{
 						Symbol:       "STOCK2",
 						Quantity:     6,
 						AveragePrice: currency.NewFromFloat(9.8),
+						Cost:         currency.NewFromFloat(58.8),
 						LastPrice:    currency.NewFromFloat(21),
 						Investment:   currency.NewFromFloat(220),
 						Settled:      currency.NewFromFloat(160),
+						Realized:     currency.NewFromFloat(-1.2),
+						DayTrade:     currency.NewFromFloat(10),
+						Income:       currency.NewFromFloat(12),
 					},
 				},
 			},
 			args: args{
 				sep: separator.Tab,
 			},
-			wantWriter: "Symbol\tQtd.\tAvg. Price\tLast Price\tGain/Loss\nSTOCK1\t8\tR$ 10,00\tR$ 12,00\t-(R$ 4,00)\nSTOCK2\t6\tR$ 9,80\tR$ 21,00\tR$ 66,00\n",
+			wantWriter: "Symbol\tClass\tQtd.\tAvg. Price\tLast Price\tRealized\tUnrealized\tDay Trade\tIncome\tYoC\tGain/Loss\nSTOCK1\tSTOCK\t8\tR$ 10,00\tR$ 12,00\t-(R$ 20,00)\tR$ 16,00\tR$ 0,00\tR$ 0,00\t0,00%\t-(R$ 4,00)\nSTOCK2\tSTOCK\t6\tR$ 9,80\tR$ 21,00\t-(R$ 1,20)\tR$ 67,20\tR$ 10,00\tR$ 12,00\t20,41%\tR$ 88,00\n",
 			wantErr:    false,
 		},
 	}
@@ -319,3 +526,42 @@ func TestAssets_Print(t *testing.T) {
 		})
 	}
 }
+
+func TestAssets_PrintUnpriced(t *testing.T) {
+	tests := []struct {
+		name       string
+		assets     Assets
+		wantWriter string
+		wantErr    bool
+	}{
+		{
+			name: "Should print nothing when every asset is priced",
+			assets: Assets{
+				{Symbol: "STOCK1", Quantity: 8},
+			},
+			wantWriter: "",
+		},
+		{
+			name: "Should list excluded symbols and their errors",
+			assets: Assets{
+				{Symbol: "STOCK1", Quantity: 8},
+				{Symbol: "STOCK2", Quantity: 5, QuoteError: errors.New("offline")},
+				{Symbol: "STOCK3", Quantity: 3, QuoteError: errors.New("not found")},
+			},
+			wantWriter: "Gain/Loss excludes STOCK2, STOCK3: quote unavailable\nSTOCK2: offline\nSTOCK3: not found\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			err := tt.assets.PrintUnpriced(writer)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PrintUnpriced() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("PrintUnpriced() gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"stocks/date"
+	"time"
+)
+
+func CreateAssetsRequest(args ...string) (time.Time, error) {
+	flags := flag.NewFlagSet("assets", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	at := flags.String("at", "", "date used to value positions")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return time.Time{}, errors.New("usage: stocks assets [--at <date>]")
+	}
+
+	if *at == "" {
+		return time.Time{}, nil
+	}
+
+	d, err := date.Parse(*at)
+	if err != nil {
+		return time.Time{}, errors.New("invalid date format")
+	}
+
+	return d, nil
+}
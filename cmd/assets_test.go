@@ -0,0 +1,64 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCreateAssetsRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "Should create request properly without date",
+			args: args{
+				args: []string{},
+			},
+			want:    time.Time{},
+			wantErr: false,
+		},
+		{
+			name: "Should create request properly with date",
+			args: args{
+				args: []string{"--at", "2023-12-31"},
+			},
+			want:    time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			wantErr: false,
+		},
+		{
+			name: "Should return error if date is invalid",
+			args: args{
+				args: []string{"--at", "2023-12"},
+			},
+			want:    time.Time{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if there are unexpected args",
+			args: args{
+				args: []string{"2023-12-31"},
+			},
+			want:    time.Time{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateAssetsRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateAssetsRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateAssetsRequest() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
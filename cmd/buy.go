@@ -2,15 +2,21 @@ package main
 
 import (
 	"errors"
+	"stocks/currency"
 	"stocks/date"
+	"stocks/operation"
 	"stocks/stock"
 	"stocks/usecase"
 	"strconv"
 )
 
 func CreateBuyRequest(args ...string) (usecase.BuyRequest, error) {
-	if len(args) < 3 || len(args) > 4 {
-		return usecase.BuyRequest{}, errors.New("usage: stocks buy <symbol> <quantity> <unit-value> [<date>]")
+	return createOperationRequest("usage: stocks buy <symbol> <quantity> <unit-value> [<date> [<brokerage> <emoluments> <settlement> <iss>]]", args...)
+}
+
+func createOperationRequest(usage string, args ...string) (usecase.BuyRequest, error) {
+	if len(args) < 3 || len(args) > 8 {
+		return usecase.BuyRequest{}, errors.New(usage)
 	}
 
 	quantity, err := strconv.Atoi(args[1])
@@ -18,13 +24,13 @@ func CreateBuyRequest(args ...string) (usecase.BuyRequest, error) {
 		return usecase.BuyRequest{}, errors.New("invalid quantity")
 	}
 
-	value, err := strconv.ParseFloat(args[2], 64)
+	value, err := currency.Parse(args[2])
 	if err != nil {
 		return usecase.BuyRequest{}, errors.New("invalid value format")
 	}
 
 	rawDate := "today"
-	if len(args) == 4 {
+	if len(args) >= 4 {
 		rawDate = args[3]
 	}
 
@@ -33,10 +39,18 @@ func CreateBuyRequest(args ...string) (usecase.BuyRequest, error) {
 		return usecase.BuyRequest{}, err
 	}
 
+	var fees operation.Fees
+	if len(args) > 4 {
+		if fees, err = operation.ParseFees(args[4:]...); err != nil {
+			return usecase.BuyRequest{}, errors.New("invalid fees format")
+		}
+	}
+
 	return usecase.BuyRequest{
 		Symbol:    stock.Symbol(args[0]),
 		Quantity:  quantity,
 		UnitValue: value,
+		Fees:      fees,
 		Date:      d,
 	}, nil
 }
@@ -2,7 +2,9 @@ package main
 
 import (
 	"reflect"
+	"stocks/currency"
 	"stocks/date"
+	"stocks/operation"
 	"stocks/usecase"
 	"testing"
 	"time"
@@ -26,7 +28,7 @@ func TestCreateBuyRequest(t *testing.T) {
 			want: usecase.BuyRequest{
 				Symbol:    "STOCK",
 				Quantity:  10,
-				UnitValue: 1.23,
+				UnitValue: currency.New(123),
 				Date:      date.Trunc(time.Now()),
 			},
 			wantErr: false,
@@ -39,11 +41,42 @@ func TestCreateBuyRequest(t *testing.T) {
 			want: usecase.BuyRequest{
 				Symbol:    "STOCK",
 				Quantity:  10,
-				UnitValue: 1.23,
+				UnitValue: currency.New(123),
 				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should build request properly with comma decimal value",
+			args: args{
+				args: []string{"STOCK", "10", "1,23", "2022-04-28"},
+			},
+			want: usecase.BuyRequest{
+				Symbol:    "STOCK",
+				Quantity:  10,
+				UnitValue: currency.New(123),
+				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should build request properly with fees",
+			args: args{
+				args: []string{"STOCK", "10", "1.23", "2022-04-28", "4.90", "0.01", "0.03"},
+			},
+			want: usecase.BuyRequest{
+				Symbol:    "STOCK",
+				Quantity:  10,
+				UnitValue: currency.New(123),
+				Fees: operation.Fees{
+					Brokerage:  currency.New(490),
+					Emoluments: currency.New(1),
+					Settlement: currency.New(3),
+				},
+				Date: time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
 		{
 			name: "Should return error if is missing args",
 			args: args{
@@ -68,6 +101,14 @@ func TestCreateBuyRequest(t *testing.T) {
 			want:    usecase.BuyRequest{},
 			wantErr: true,
 		},
+		{
+			name: "Should return error if fees are invalid",
+			args: args{
+				args: []string{"STOCK", "10", "1.23", "2022-04-28", "abc"},
+			},
+			want:    usecase.BuyRequest{},
+			wantErr: true,
+		},
 		{
 			name: "Should return error if date is invalid",
 			args: args{
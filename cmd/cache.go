@@ -0,0 +1,23 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultCacheTTL = 15 * time.Minute
+)
+
+func ParseCacheTTL(raw string) (time.Duration, error) {
+	if raw == "" {
+		return defaultCacheTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl < 0 {
+		return 0, errors.New("invalid STOCKS_CACHE_TTL, expected a duration like 15m or 1h")
+	}
+
+	return ttl, nil
+}
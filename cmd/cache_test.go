@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCacheTTL(t *testing.T) {
+	type args struct {
+		raw string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "Should use default ttl when empty",
+			args: args{
+				raw: "",
+			},
+			want:    defaultCacheTTL,
+			wantErr: false,
+		},
+		{
+			name: "Should parse duration properly",
+			args: args{
+				raw: "1h",
+			},
+			want:    time.Hour,
+			wantErr: false,
+		},
+		{
+			name: "Should return error if duration is invalid",
+			args: args{
+				raw: "abc",
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "Should return error if duration is negative",
+			args: args{
+				raw: "-1h",
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCacheTTL(tt.args.raw)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCacheTTL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseCacheTTL() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"errors"
+	"stocks/stock"
+	"strings"
+)
+
+func CreateCNPJRequest(args ...string) (stock.Symbol, string, error) {
+	if len(args) != 2 || args[0] == "" {
+		return "", "", errors.New("usage: stocks cnpj <symbol> <cnpj>")
+	}
+
+	cnpj, err := stock.ParseCNPJ(args[1])
+	if err != nil {
+		return "", "", err
+	}
+
+	return stock.Symbol(strings.ToUpper(args[0])), cnpj, nil
+}
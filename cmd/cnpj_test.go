@@ -0,0 +1,58 @@
+package main
+
+import (
+	"stocks/stock"
+	"testing"
+)
+
+func TestCreateCNPJRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     stock.Symbol
+		wantCNPJ string
+		wantErr  bool
+	}{
+		{
+			name: "Should create request properly",
+			args: args{
+				args: []string{"petr4", "33000167000101"},
+			},
+			want:     "PETR4",
+			wantCNPJ: "33.000.167/0001-01",
+			wantErr:  false,
+		},
+		{
+			name: "Should return error if cnpj is missing",
+			args: args{
+				args: []string{"PETR4"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if cnpj is invalid",
+			args: args{
+				args: []string{"PETR4", "33000167000102"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotCNPJ, err := CreateCNPJRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateCNPJRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CreateCNPJRequest() got = %v, want %v", got, tt.want)
+			}
+			if gotCNPJ != tt.wantCNPJ {
+				t.Errorf("CreateCNPJRequest() gotCNPJ = %v, want %v", gotCNPJ, tt.wantCNPJ)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"stocks/usecase"
+	"strconv"
+	"strings"
+)
+
+func CreateEditRequest(args ...string) (usecase.EditRequest, error) {
+	if len(args) < 2 {
+		return usecase.EditRequest{}, errors.New("usage: stocks edit <id> <field>=<value>...")
+	}
+
+	id, err := parseID(args[0])
+	if err != nil {
+		return usecase.EditRequest{}, err
+	}
+
+	fields := make(map[string]string, len(args)-1)
+	for _, arg := range args[1:] {
+		field, value, found := strings.Cut(arg, "=")
+		if !found || field == "" {
+			return usecase.EditRequest{}, fmt.Errorf("invalid field: %s", arg)
+		}
+
+		fields[strings.ToLower(field)] = value
+	}
+
+	return usecase.EditRequest{
+		ID:     id,
+		Fields: fields,
+	}, nil
+}
+
+func parseID(raw string) (uint, error) {
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.New("invalid operation id")
+	}
+
+	return uint(id), nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"reflect"
+	"stocks/usecase"
+	"testing"
+)
+
+func TestCreateEditRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    usecase.EditRequest
+		wantErr bool
+	}{
+		{
+			name: "Should build request properly",
+			args: args{
+				args: []string{"12", "quantity=10", "Value=1,23"},
+			},
+			want: usecase.EditRequest{
+				ID: 12,
+				Fields: map[string]string{
+					"quantity": "10",
+					"value":    "1,23",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should keep empty values",
+			args: args{
+				args: []string{"12", "irrf="},
+			},
+			want: usecase.EditRequest{
+				ID: 12,
+				Fields: map[string]string{
+					"irrf": "",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return error if is missing fields",
+			args: args{
+				args: []string{"12"},
+			},
+			want:    usecase.EditRequest{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if id is invalid",
+			args: args{
+				args: []string{"abc", "quantity=10"},
+			},
+			want:    usecase.EditRequest{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if field is malformed",
+			args: args{
+				args: []string{"12", "quantity"},
+			},
+			want:    usecase.EditRequest{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateEditRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateEditRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateEditRequest() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
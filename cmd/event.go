@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"stocks/currency"
+	"stocks/date"
+	"stocks/operation"
+	"stocks/stock"
+	"stocks/usecase"
+)
+
+const (
+	targetFlag = "--to"
+)
+
+func CreateEventRequest(args ...string) (usecase.EventRequest, error) {
+	usage := errors.New("usage: stocks event <split|reverse-split|bonus|conversion> <symbol> <ratio> [<date> [<unit-cost>]] [--to <symbol>]")
+
+	target, args, ok := extractFlag(targetFlag, args...)
+	if !ok || len(args) < 3 || len(args) > 5 {
+		return usecase.EventRequest{}, usage
+	}
+
+	types := map[string]operation.Type{
+		"split":         operation.Split,
+		"reverse-split": operation.ReverseSplit,
+		"bonus":         operation.Bonus,
+		"conversion":    operation.Conversion,
+	}
+
+	t, ok := types[args[0]]
+	if !ok {
+		return usecase.EventRequest{}, errors.New("invalid event type")
+	}
+
+	ratio, err := operation.ParseRatio(args[2])
+	if err != nil {
+		return usecase.EventRequest{}, errors.New("invalid ratio format")
+	}
+
+	rawDate := "today"
+	if len(args) >= 4 {
+		rawDate = args[3]
+	}
+
+	d, err := date.Parse(rawDate)
+	if err != nil {
+		return usecase.EventRequest{}, err
+	}
+
+	var unitValue currency.Currency
+	if len(args) == 5 {
+		if unitValue, err = currency.Parse(args[4]); err != nil {
+			return usecase.EventRequest{}, errors.New("invalid value format")
+		}
+	}
+
+	return usecase.EventRequest{
+		Type:      t,
+		Symbol:    stock.Symbol(args[1]),
+		Ratio:     ratio,
+		UnitValue: unitValue,
+		Date:      d,
+		Target:    stock.Symbol(target),
+	}, nil
+}
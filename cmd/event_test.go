@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"stocks/currency"
+	"stocks/date"
+	"stocks/operation"
+	"stocks/usecase"
+	"testing"
+	"time"
+)
+
+func TestCreateEventRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    usecase.EventRequest
+		wantErr bool
+	}{
+		{
+			name: "Should build split request properly without date",
+			args: args{
+				args: []string{"split", "STOCK", "1:4"},
+			},
+			want: usecase.EventRequest{
+				Type:   operation.Split,
+				Symbol: "STOCK",
+				Ratio:  4,
+				Date:   date.Trunc(time.Now()),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should build conversion request properly with target",
+			args: args{
+				args: []string{"conversion", "FUND13", "1", "2022-04-28", "--to", "FUND11"},
+			},
+			want: usecase.EventRequest{
+				Type:   operation.Conversion,
+				Symbol: "FUND13",
+				Ratio:  1,
+				Date:   time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+				Target: "FUND11",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should build bonus request properly with unit cost",
+			args: args{
+				args: []string{"bonus", "STOCK", "1.1", "2022-04-28", "12.34"},
+			},
+			want: usecase.EventRequest{
+				Type:      operation.Bonus,
+				Symbol:    "STOCK",
+				Ratio:     1.1,
+				UnitValue: currency.New(1234),
+				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return error if is missing args",
+			args: args{
+				args: []string{"split", "STOCK"},
+			},
+			want:    usecase.EventRequest{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if event type is invalid",
+			args: args{
+				args: []string{"merge", "STOCK", "2"},
+			},
+			want:    usecase.EventRequest{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if ratio is invalid",
+			args: args{
+				args: []string{"split", "STOCK", "abc"},
+			},
+			want:    usecase.EventRequest{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if unit cost is invalid",
+			args: args{
+				args: []string{"bonus", "STOCK", "1.1", "2022-04-28", "abc"},
+			},
+			want:    usecase.EventRequest{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateEventRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateEventRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateEventRequest() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
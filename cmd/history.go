@@ -0,0 +1,15 @@
+package main
+
+import (
+	"errors"
+	"stocks/stock"
+	"strings"
+)
+
+func CreateHistoryRequest(args ...string) (stock.Symbol, error) {
+	if len(args) != 1 || args[0] == "" {
+		return "", errors.New("usage: stocks history <symbol>")
+	}
+
+	return stock.Symbol(strings.ToUpper(args[0])), nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+)
+
+func CreateImportRequest(args ...string) (string, bool, error) {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	dryRun := flags.Bool("dry-run", false, "print operations without importing them")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return "", false, errors.New("usage: stocks import|import-b3 [--dry-run] <source>")
+	}
+
+	return flags.Arg(0), *dryRun, nil
+}
+
+type NoteImportRequest struct {
+	Source  string
+	Symbols string
+	DryRun  bool
+}
+
+func CreateNoteImportRequest(args ...string) (NoteImportRequest, error) {
+	flags := flag.NewFlagSet("import-note", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	dryRun := flags.Bool("dry-run", false, "print operations without importing them")
+	symbols := flags.String("symbols", "", "CSV mapping note specifications to symbols")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return NoteImportRequest{}, errors.New("usage: stocks import-note [--dry-run] [--symbols <file>] <note.pdf|note.txt>")
+	}
+
+	return NoteImportRequest{
+		Source:  flags.Arg(0),
+		Symbols: *symbols,
+		DryRun:  *dryRun,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCreateImportRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       string
+		wantDryRun bool
+		wantErr    bool
+	}{
+		{
+			name: "Should create request properly",
+			args: args{
+				args: []string{"stocks.csv"},
+			},
+			want:       "stocks.csv",
+			wantDryRun: false,
+			wantErr:    false,
+		},
+		{
+			name: "Should create dry run request properly",
+			args: args{
+				args: []string{"--dry-run", "stocks.csv"},
+			},
+			want:       "stocks.csv",
+			wantDryRun: true,
+			wantErr:    false,
+		},
+		{
+			name: "Should return error if source is missing",
+			args: args{
+				args: []string{"--dry-run"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if flag is unknown",
+			args: args{
+				args: []string{"--force", "stocks.csv"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotDryRun, err := CreateImportRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateImportRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CreateImportRequest() got = %v, want %v", got, tt.want)
+			}
+			if gotDryRun != tt.wantDryRun {
+				t.Errorf("CreateImportRequest() gotDryRun = %v, want %v", gotDryRun, tt.wantDryRun)
+			}
+		})
+	}
+}
+
+func TestCreateNoteImportRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    NoteImportRequest
+		wantErr bool
+	}{
+		{
+			name: "Should create request properly",
+			args: args{
+				args: []string{"note.pdf"},
+			},
+			want: NoteImportRequest{Source: "note.pdf"},
+		},
+		{
+			name: "Should create request with symbols and dry run properly",
+			args: args{
+				args: []string{"--dry-run", "--symbols", "symbols.csv", "note.txt"},
+			},
+			want: NoteImportRequest{Source: "note.txt", Symbols: "symbols.csv", DryRun: true},
+		},
+		{
+			name: "Should return error if source is missing",
+			args: args{
+				args: []string{"--symbols", "symbols.csv"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateNoteImportRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateNoteImportRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CreateNoteImportRequest() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
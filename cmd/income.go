@@ -0,0 +1,24 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+func CreateIncomeRequest(args ...string) (time.Time, time.Time, error) {
+	switch len(args) {
+	case 0:
+		return time.Time{}, time.Now().AddDate(1, 0, 0), nil
+	case 1:
+		year, err := strconv.Atoi(args[0])
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid year")
+		}
+
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(1, 0, 0), nil
+	default:
+		return time.Time{}, time.Time{}, errors.New("usage: stocks income [<year>]")
+	}
+}
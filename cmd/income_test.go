@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCreateIncomeRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{
+			name: "Should create request properly with year",
+			args: args{
+				args: []string{"2022"},
+			},
+			wantFrom: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantErr:  false,
+		},
+		{
+			name: "Should return error if year is invalid",
+			args: args{
+				args: []string{"abc"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if has too many args",
+			args: args{
+				args: []string{"2022", "2023"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFrom, gotTo, err := CreateIncomeRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateIncomeRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotFrom, tt.wantFrom) {
+				t.Errorf("CreateIncomeRequest() gotFrom = %v, want %v", gotFrom, tt.wantFrom)
+			}
+			if !reflect.DeepEqual(gotTo, tt.wantTo) {
+				t.Errorf("CreateIncomeRequest() gotTo = %v, want %v", gotTo, tt.wantTo)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+)
+
+func CreateIRPFRequest(args ...string) (int, error) {
+	if len(args) != 1 {
+		return 0, errors.New("usage: stocks irpf <year>")
+	}
+
+	year, err := strconv.Atoi(args[0])
+	if err != nil || len(args[0]) != 4 {
+		return 0, errors.New("invalid year")
+	}
+
+	return year, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestCreateIRPFRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "Should create request properly",
+			args: args{
+				args: []string{"2022"},
+			},
+			want:    2022,
+			wantErr: false,
+		},
+		{
+			name: "Should return error if year arg is missing",
+			args: args{
+				args: []string{},
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "Should return error if year is invalid",
+			args: args{
+				args: []string{"2022-04"},
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateIRPFRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateIRPFRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CreateIRPFRequest() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
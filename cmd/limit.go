@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"stocks/stock"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxInFlight = 4
+	defaultRateLimit   = 5
+	defaultRetries     = 3
+	defaultBackoff     = 500 * time.Millisecond
+)
+
+func ParseLimits(maxInFlight, rateLimit string) (stock.Limits, error) {
+	limits := stock.Limits{
+		MaxInFlight: defaultMaxInFlight,
+		PerSecond:   defaultRateLimit,
+		Retries:     defaultRetries,
+		Backoff:     defaultBackoff,
+	}
+
+	if maxInFlight != "" {
+		value, err := strconv.Atoi(maxInFlight)
+		if err != nil || value < 1 {
+			return stock.Limits{}, errors.New("invalid STOCKS_MAX_IN_FLIGHT, expected a positive integer")
+		}
+
+		limits.MaxInFlight = value
+	}
+
+	if rateLimit != "" {
+		value, err := strconv.ParseFloat(rateLimit, 64)
+		if err != nil || value < 0 {
+			return stock.Limits{}, errors.New("invalid STOCKS_RATE_LIMIT, expected requests per second")
+		}
+
+		limits.PerSecond = value
+	}
+
+	return limits, nil
+}
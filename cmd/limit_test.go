@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"stocks/stock"
+	"testing"
+)
+
+func TestParseLimits(t *testing.T) {
+	type args struct {
+		maxInFlight string
+		rateLimit   string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    stock.Limits
+		wantErr bool
+	}{
+		{
+			name: "Should use default limits when empty",
+			args: args{},
+			want: stock.Limits{
+				MaxInFlight: defaultMaxInFlight,
+				PerSecond:   defaultRateLimit,
+				Retries:     defaultRetries,
+				Backoff:     defaultBackoff,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should parse limits properly",
+			args: args{
+				maxInFlight: "2",
+				rateLimit:   "0.5",
+			},
+			want: stock.Limits{
+				MaxInFlight: 2,
+				PerSecond:   0.5,
+				Retries:     defaultRetries,
+				Backoff:     defaultBackoff,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return error if max in flight is not positive",
+			args: args{
+				maxInFlight: "0",
+			},
+			want:    stock.Limits{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if rate limit is invalid",
+			args: args{
+				rateLimit: "fast",
+			},
+			want:    stock.Limits{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLimits(tt.args.maxInFlight, tt.args.rateLimit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseLimits() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLimits() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,8 +8,15 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"sort"
+	"stocks/asset"
+	"stocks/internal/brapi"
+	"stocks/internal/csvprice"
 	"stocks/internal/mfinance"
 	"stocks/internal/repository"
+	"stocks/internal/sinacor"
+	"stocks/internal/yahoo"
+	"stocks/operation"
 	"stocks/separator"
 	"stocks/stock"
 	"stocks/usecase"
@@ -18,9 +25,28 @@ import (
 var (
 	lastPriceUseCase          *usecase.GetLastPrice
 	createBuyOperationUseCase *usecase.BuyOperationUseCase
+	sellOperationUseCase      *usecase.SellOperationUseCase
+	eventOperationUseCase     *usecase.EventOperationUseCase
+	rightsUseCase             *usecase.RightsUseCase
+	subscribeUseCase          *usecase.SubscribeUseCase
 	listUseCase               *usecase.ListUseCase
+	positionHistoryUseCase    *usecase.PositionHistoryUseCase
+	realizedUseCase           *usecase.RealizedUseCase
 	importUseCase             *usecase.ImportUseCase
 	assetsUseCase             *usecase.AssetsUseCase
+	historicalAssetsUseCase   *usecase.HistoricalAssetsUseCase
+	taxUseCase                *usecase.TaxUseCase
+	irpfUseCase               *usecase.IRPFUseCase
+	setCNPJUseCase            *usecase.SetCNPJUseCase
+	incomeImportUseCase       *usecase.IncomeImportUseCase
+	incomeReportUseCase       *usecase.IncomeReportUseCase
+	createPortfolioUseCase    *usecase.CreatePortfolioUseCase
+	listPortfoliosUseCase     *usecase.ListPortfoliosUseCase
+	setMethodUseCase          *usecase.SetMethodUseCase
+	editOperationUseCase      *usecase.EditOperationUseCase
+	deleteOperationUseCase    *usecase.DeleteOperationUseCase
+	changesUseCase            *usecase.ChangesUseCase
+	b3ImportUseCase           *usecase.B3ImportUseCase
 )
 
 func init() {
@@ -29,15 +55,80 @@ func init() {
 		log.Fatalln("failed to connect database")
 	}
 
-	database := repository.NewGormDatabase(db)
-	provider := mfinance.NewProvider(http.DefaultClient)
+	database, err := repository.NewGormDatabase(db)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	limits, err := ParseLimits(os.Getenv("STOCKS_MAX_IN_FLIGHT"), os.Getenv("STOCKS_RATE_LIMIT"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	provider, err := createProvider(limits)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
 	fetcher := stock.NewFetcher(database, provider)
 
+	ttl, err := ParseCacheTTL(os.Getenv("STOCKS_CACHE_TTL"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	cachedProvider := stock.NewCachedProvider(provider, database, ttl)
+
 	lastPriceUseCase = usecase.NewGetLastPrice(provider)
 	createBuyOperationUseCase = usecase.NewBuyOperationUseCase(database, fetcher)
+	sellOperationUseCase = usecase.NewSellOperationUseCase(database, database)
+	eventOperationUseCase = usecase.NewEventOperationUseCase(database)
+	rightsUseCase = usecase.NewRightsUseCase(database)
+	subscribeUseCase = usecase.NewSubscribeUseCase(database)
 	listUseCase = usecase.NewListUseCase(database)
+	positionHistoryUseCase = usecase.NewPositionHistoryUseCase(database, database)
+	realizedUseCase = usecase.NewRealizedUseCase(database, database)
 	importUseCase = usecase.NewImportUseCase(database, fetcher)
-	assetsUseCase = usecase.NewAssetsUseCase(provider, database)
+	assetsUseCase = usecase.NewAssetsUseCase(cachedProvider, database)
+	historicalAssetsUseCase = usecase.NewHistoricalAssetsUseCase(provider, database, database)
+	taxUseCase = usecase.NewTaxUseCase(database, database)
+	irpfUseCase = usecase.NewIRPFUseCase(database, database, database)
+	setCNPJUseCase = usecase.NewSetCNPJUseCase(database, fetcher)
+	incomeImportUseCase = usecase.NewIncomeImportUseCase(database, fetcher)
+	incomeReportUseCase = usecase.NewIncomeReportUseCase(database)
+	createPortfolioUseCase = usecase.NewCreatePortfolioUseCase(database)
+	listPortfoliosUseCase = usecase.NewListPortfoliosUseCase(database)
+	setMethodUseCase = usecase.NewSetMethodUseCase(database)
+	editOperationUseCase = usecase.NewEditOperationUseCase(database, fetcher)
+	deleteOperationUseCase = usecase.NewDeleteOperationUseCase(database)
+	changesUseCase = usecase.NewChangesUseCase(database)
+	b3ImportUseCase = usecase.NewB3ImportUseCase(database, database, database, database, fetcher)
+}
+
+func createProvider(limits stock.Limits) (*stock.CompositeProvider, error) {
+	priceFile := os.Getenv("STOCKS_PRICE_FILE")
+
+	order, err := ParseProviderOrder(os.Getenv("STOCKS_PROVIDERS"), priceFile)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := ParseProviderOverrides(os.Getenv("STOCKS_PROVIDER_OVERRIDES"))
+	if err != nil {
+		return nil, err
+	}
+
+	providers := map[string]stock.Provider{
+		mfinanceProvider: stock.NewLimitedProvider(mfinance.NewProvider(http.DefaultClient), limits),
+		brapiProvider:    stock.NewLimitedProvider(brapi.NewProvider(http.DefaultClient, os.Getenv("BRAPI_TOKEN")), limits),
+		yahooProvider:    stock.NewLimitedProvider(yahoo.NewProvider(http.DefaultClient), limits),
+	}
+
+	if priceFile != "" {
+		providers[csvProvider] = csvprice.NewProvider(priceFile)
+	}
+
+	return stock.NewCompositeProvider(providers, order, overrides)
 }
 
 func main() {
@@ -47,33 +138,133 @@ func main() {
 		log.Fatalln("usage stocks [command] <options...>")
 	}
 
+	name, args, err := ParsePortfolio(os.Args[2:]...)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
 	switch os.Args[1] {
 	case "buy":
-		request, err := CreateBuyRequest(os.Args[2:]...)
+		request, err := CreateBuyRequest(args...)
 		if err != nil {
 			log.Fatalln(err)
 		}
 
+		request.Portfolio = name
+
 		operation, err := createBuyOperationUseCase.Execute(ctx, request)
 		if err != nil {
 			log.Fatalln(err)
 		}
 
 		log.Printf("operation created succesffully: %v\n", operation)
+	case "sell":
+		request, err := CreateSellRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		request.Portfolio = name
+
+		response, err := sellOperationUseCase.Execute(ctx, request)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		log.Printf("operation created succesffully: %v\n", response.Operation)
+		log.Printf("average price: %s, realized gain: %s, day trade gain: %s\n",
+			response.AveragePrice, response.Gain, response.DayTradeGain)
+	case "event":
+		if name != "" {
+			log.Fatalln("corporate events apply to every portfolio")
+		}
+
+		request, err := CreateEventRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		operation, err := eventOperationUseCase.Execute(ctx, request)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		log.Printf("operation created succesffully: %v\n", operation)
+	case "rights":
+		request, err := CreateRightsRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		request.Portfolio = name
+
+		operation, err := rightsUseCase.Execute(ctx, request)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		log.Printf("operation created succesffully: %v\n", operation)
+	case "subscribe":
+		request, err := CreateSubscribeRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		request.Portfolio = name
+
+		operation, err := subscribeUseCase.Execute(ctx, request)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		log.Printf("operation created succesffully: %v\n", operation)
+	case "edit":
+		request, err := CreateEditRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		operation, err := editOperationUseCase.Execute(ctx, request)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		log.Printf("operation updated succesffully: %v\n", operation)
+	case "rm":
+		id, err := CreateRemoveRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		operation, err := deleteOperationUseCase.Execute(ctx, id)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		log.Printf("operation removed succesffully: %v\n", operation)
+	case "audit":
+		changes, err := changesUseCase.Execute(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		if err := changes.Print(os.Stdout, separator.Tab); err != nil {
+			log.Fatalln(err)
+		}
 	case "price":
-		request, err := CreatePriceRequest(os.Args[2:]...)
+		request, err := CreatePriceRequest(args...)
 		if err != nil {
 			log.Fatalln(err)
 		}
 
-		lastPrice, err := lastPriceUseCase.Execute(ctx, request)
+		info, err := lastPriceUseCase.Execute(ctx, request)
 		if err != nil {
 			log.Fatalln(err)
 		}
 
-		fmt.Printf("%s: %.2f\n", request, lastPrice)
+		fmt.Printf("%s: %s (%s)\n", request, info.LastPrice, info.Source)
 	case "list":
-		operations, err := listUseCase.Execute(ctx)
+		operations, err := listUseCase.Execute(ctx, name)
 		if err != nil {
 			log.Fatalln(err)
 		}
@@ -81,10 +272,40 @@ func main() {
 		if err := operations.Print(os.Stdout, separator.Tab); err != nil {
 			log.Fatalln(err)
 		}
+	case "history":
+		symbol, err := CreateHistoryRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		history, err := positionHistoryUseCase.Execute(ctx, name, symbol)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		if err := history.Print(os.Stdout, separator.Tab); err != nil {
+			log.Fatalln(err)
+		}
+	case "realized":
+		from, to, err := CreateRealizedRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		sales, err := realizedUseCase.Execute(ctx, name, from, to)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		if err := sales.Print(os.Stdout, separator.Tab); err != nil {
+			log.Fatalln(err)
+		}
+
+		fmt.Printf("\nTotal\t\t\t\t\t\t\t\t%s\n", sales.Result())
 	case "export":
 		output := "stocks.csv"
-		if len(os.Args) > 2 {
-			output = os.Args[2]
+		if len(args) > 0 {
+			output = args[0]
 		}
 
 		file, err := os.Create(output)
@@ -96,7 +317,7 @@ func main() {
 			_ = file.Close()
 		}()
 
-		operations, err := listUseCase.Execute(ctx)
+		operations, err := listUseCase.Execute(ctx, name)
 		if err != nil {
 			log.Fatalln(err)
 		}
@@ -105,23 +326,129 @@ func main() {
 			log.Fatalln(err)
 		}
 	case "import":
-		if len(os.Args) < 3 {
-			log.Fatalln("usage: stocks import <source>")
+		source, dryRun, err := CreateImportRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
 		}
 
-		file, err := os.Open(os.Args[2])
+		file, err := os.Open(source)
 		if err != nil {
 			log.Fatalln(err)
 		}
 
-		operations, err := importUseCase.Execute(ctx, file)
+		result, err := importUseCase.Execute(ctx, file, name, dryRun)
 		if err != nil {
 			log.Fatalln(err)
 		}
 
-		fmt.Printf("imported %d operations succesfully\n", len(operations))
+		printReconciliation(result, dryRun)
+	case "import-note":
+		request, err := CreateNoteImportRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		symbols := map[string]stock.Symbol{}
+		if request.Symbols != "" {
+			file, err := os.Open(request.Symbols)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			if symbols, err = sinacor.ReadSymbols(file); err != nil {
+				log.Fatalln(err)
+			}
+		}
+
+		file, err := os.Open(request.Source)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		result, err := importUseCase.ExecuteNotes(ctx, file, symbols, name, request.DryRun)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		printReconciliation(result, request.DryRun)
+	case "import-b3":
+		source, dryRun, err := CreateImportRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		file, err := os.Open(source)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		result, err := b3ImportUseCase.Execute(ctx, file, name, dryRun)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		movements := make([]string, 0, len(result.Ignored))
+		for movement := range result.Ignored {
+			movements = append(movements, movement)
+		}
+		sort.Strings(movements)
+
+		for _, movement := range movements {
+			fmt.Printf("ignored %d rows of %q\n", result.Ignored[movement], movement)
+		}
+
+		printReconciliation(result.Operations, dryRun)
+
+		if dryRun {
+			fmt.Printf("would insert %d incomes\n", len(result.Incomes))
+		} else {
+			fmt.Printf("inserted %d incomes\n", len(result.Incomes))
+		}
+	case "import-income":
+		if len(args) < 1 {
+			log.Fatalln("usage: stocks import-income <source>")
+		}
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		incomes, err := incomeImportUseCase.Execute(ctx, file, name)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		fmt.Printf("imported %d incomes succesfully\n", len(incomes))
+	case "income":
+		from, to, err := CreateIncomeRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		report, err := incomeReportUseCase.Execute(ctx, name, from, to)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		if err := report.Print(os.Stdout, separator.Tab); err != nil {
+			log.Fatalln(err)
+		}
+
+		fmt.Printf("\nTotal\t\t\t\t\t\t\t\t\t\t%s\n", report.Net())
 	case "assets":
-		assets, err := assetsUseCase.Execute(ctx)
+		at, err := CreateAssetsRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		var assets asset.Assets
+		if at.IsZero() {
+			assets, err = assetsUseCase.Execute(ctx, name)
+		} else {
+			assets, err = historicalAssetsUseCase.Execute(ctx, name, at)
+		}
+
 		if err != nil {
 			log.Fatalln(err)
 		}
@@ -130,6 +457,125 @@ func main() {
 			log.Fatalln(err)
 		}
 
-		fmt.Printf("\nTotal\t\t\t\t\t\t%s\n", assets.GainLoss())
+		fmt.Printf("\nTotal\t\t\t\t\t%s\t%s\t%s\t%s\t\t%s\n", assets.Realized(), assets.Unrealized(), assets.DayTrade(),
+			assets.Income(), assets.GainLoss())
+
+		if err := assets.PrintUnpriced(os.Stdout); err != nil {
+			log.Fatalln(err)
+		}
+	case "tax":
+		if name != "" {
+			log.Fatalln("tax is calculated over every portfolio")
+		}
+
+		period, err := CreateTaxRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		report, err := taxUseCase.Execute(ctx, period)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		if err := report.Print(os.Stdout, separator.Tab); err != nil {
+			log.Fatalln(err)
+		}
+	case "irpf":
+		if name != "" {
+			log.Fatalln("irpf is calculated over every portfolio")
+		}
+
+		year, err := CreateIRPFRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		report, err := irpfUseCase.Execute(ctx, year)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		if err := report.Print(os.Stdout, separator.Tab); err != nil {
+			log.Fatalln(err)
+		}
+	case "cnpj":
+		symbol, cnpj, err := CreateCNPJRequest(args...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		details, err := setCNPJUseCase.Execute(ctx, symbol, cnpj)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		log.Printf("%s (%s) now has CNPJ %s\n", details.Symbol, details.Name, details.CNPJ)
+	case "portfolio":
+		if len(args) < 1 {
+			log.Fatalln("usage: stocks portfolio <add|list|method>")
+		}
+
+		switch args[0] {
+		case "add":
+			request, err := CreatePortfolioRequest(args[1:]...)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			p, err := createPortfolioUseCase.Execute(ctx, request)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			log.Printf("portfolio created succesffully: %s\n", p.Name)
+		case "list":
+			portfolios, err := listPortfoliosUseCase.Execute(ctx)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			if err := portfolios.Print(os.Stdout, separator.Tab); err != nil {
+				log.Fatalln(err)
+			}
+		case "method":
+			name, method, err := CreateMethodRequest(args[1:]...)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			p, err := setMethodUseCase.Execute(ctx, name, method)
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			log.Printf("portfolio %s now uses %s cost basis\n", p.Name, p.Method)
+		default:
+			log.Fatalln("usage: stocks portfolio <add|list|method>")
+		}
+	}
+}
+
+func printReconciliation(result operation.Reconciliation, dryRun bool) {
+	if dryRun && len(result.Inserted) > 0 {
+		fmt.Println("Would insert:")
+		if err := result.Inserted.Print(os.Stdout, separator.Tab); err != nil {
+			log.Fatalln(err)
+		}
 	}
+
+	if len(result.Conflicting) > 0 {
+		fmt.Println("Conflicting with stored operations (not imported):")
+		if err := result.Conflicting.Print(os.Stdout, separator.Tab); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	inserted := "inserted"
+	if dryRun {
+		inserted = "would insert"
+	}
+
+	fmt.Printf("%s %d, skipped %d, conflicting %d operations\n",
+		inserted, len(result.Inserted), len(result.Skipped), len(result.Conflicting))
 }
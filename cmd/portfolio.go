@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"stocks/operation"
+	"stocks/portfolio"
+	"strings"
+)
+
+const (
+	portfolioFlag = "--portfolio"
+)
+
+func ParsePortfolio(args ...string) (string, []string, error) {
+	name, rest, ok := extractFlag(portfolioFlag, args...)
+	if !ok || (name == "" && len(rest) < len(args)) {
+		return "", nil, errors.New("usage: --portfolio <name>")
+	}
+
+	return name, rest, nil
+}
+
+func extractFlag(name string, args ...string) (string, []string, bool) {
+	var value string
+	rest := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == name:
+			if i+1 >= len(args) {
+				return "", nil, false
+			}
+
+			i++
+			value = args[i]
+		case strings.HasPrefix(args[i], name+"="):
+			value = strings.TrimPrefix(args[i], name+"=")
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+
+	return value, rest, true
+}
+
+func CreatePortfolioRequest(args ...string) (portfolio.Portfolio, error) {
+	usage := errors.New("usage: stocks portfolio add [--method average|fifo|specific] <name> [<broker>]")
+
+	flags := flag.NewFlagSet("portfolio add", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	rawMethod := flags.String("method", operation.Average.String(), "cost basis method")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() < 1 || flags.NArg() > 2 || flags.Arg(0) == "" {
+		return portfolio.Portfolio{}, usage
+	}
+
+	method, err := operation.ParseMethod(*rawMethod)
+	if err != nil {
+		return portfolio.Portfolio{}, err
+	}
+
+	return portfolio.Portfolio{
+		Name:   flags.Arg(0),
+		Broker: flags.Arg(1),
+		Method: method,
+	}, nil
+}
+
+func CreateMethodRequest(args ...string) (string, operation.Method, error) {
+	if len(args) != 2 || args[0] == "" {
+		return "", operation.Average, errors.New("usage: stocks portfolio method <name> <average|fifo|specific>")
+	}
+
+	method, err := operation.ParseMethod(args[1])
+	if err != nil {
+		return "", operation.Average, err
+	}
+
+	return args[0], method, nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"reflect"
+	"stocks/operation"
+	"stocks/portfolio"
+	"testing"
+)
+
+func TestParsePortfolio(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{
+			name: "Should return remaining args without portfolio",
+			args: args{
+				args: []string{"STOCK", "10", "1.23"},
+			},
+			want:     "",
+			wantArgs: []string{"STOCK", "10", "1.23"},
+			wantErr:  false,
+		},
+		{
+			name: "Should parse portfolio flag with separate value",
+			args: args{
+				args: []string{"--portfolio", "xp", "STOCK", "10", "1.23"},
+			},
+			want:     "xp",
+			wantArgs: []string{"STOCK", "10", "1.23"},
+			wantErr:  false,
+		},
+		{
+			name: "Should parse portfolio flag with inline value anywhere",
+			args: args{
+				args: []string{"--at", "2023-12-31", "--portfolio=rico"},
+			},
+			want:     "rico",
+			wantArgs: []string{"--at", "2023-12-31"},
+			wantErr:  false,
+		},
+		{
+			name: "Should return error if portfolio value is missing",
+			args: args{
+				args: []string{"STOCK", "--portfolio"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if inline portfolio value is empty",
+			args: args{
+				args: []string{"--portfolio="},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotArgs, err := ParsePortfolio(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePortfolio() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParsePortfolio() got = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("ParsePortfolio() gotArgs = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCreatePortfolioRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    portfolio.Portfolio
+		wantErr bool
+	}{
+		{
+			name: "Should create request properly without broker",
+			args: args{
+				args: []string{"xp"},
+			},
+			want:    portfolio.Portfolio{Name: "xp"},
+			wantErr: false,
+		},
+		{
+			name: "Should create request properly with broker",
+			args: args{
+				args: []string{"xp", "XP Investimentos"},
+			},
+			want:    portfolio.Portfolio{Name: "xp", Broker: "XP Investimentos"},
+			wantErr: false,
+		},
+		{
+			name: "Should create request properly with method",
+			args: args{
+				args: []string{"--method", "fifo", "us", "Interactive Brokers"},
+			},
+			want:    portfolio.Portfolio{Name: "us", Broker: "Interactive Brokers", Method: operation.FIFO},
+			wantErr: false,
+		},
+		{
+			name: "Should return error if method is unknown",
+			args: args{
+				args: []string{"--method", "lifo", "us"},
+			},
+			want:    portfolio.Portfolio{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if name is missing",
+			args: args{
+				args: []string{},
+			},
+			want:    portfolio.Portfolio{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreatePortfolioRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreatePortfolioRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreatePortfolioRequest() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateMethodRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       string
+		wantMethod operation.Method
+		wantErr    bool
+	}{
+		{
+			name: "Should create request properly",
+			args: args{
+				args: []string{"crypto", "specific"},
+			},
+			want:       "crypto",
+			wantMethod: operation.SpecificLot,
+			wantErr:    false,
+		},
+		{
+			name: "Should return error if method is missing",
+			args: args{
+				args: []string{"crypto"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if method is unknown",
+			args: args{
+				args: []string{"crypto", "lifo"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotMethod, err := CreateMethodRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateMethodRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CreateMethodRequest() got = %v, want %v", got, tt.want)
+			}
+			if gotMethod != tt.wantMethod {
+				t.Errorf("CreateMethodRequest() gotMethod = %v, want %v", gotMethod, tt.wantMethod)
+			}
+		})
+	}
+}
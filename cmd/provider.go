@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"stocks/stock"
+	"strings"
+)
+
+const (
+	mfinanceProvider = "mfinance"
+	brapiProvider    = "brapi"
+	yahooProvider    = "yahoo"
+	csvProvider      = "csv"
+)
+
+var defaultProviders = []string{mfinanceProvider, brapiProvider, yahooProvider}
+
+func ParseProviderOrder(raw, priceFile string) ([]string, error) {
+	if strings.TrimSpace(raw) != "" {
+		return parseProviderNames(raw)
+	}
+
+	order := append([]string{}, defaultProviders...)
+	if priceFile != "" {
+		order = append(order, csvProvider)
+	}
+
+	return order, nil
+}
+
+func ParseProviderOverrides(raw string) (map[stock.Symbol][]string, error) {
+	overrides := map[stock.Symbol][]string{}
+
+	for _, entry := range strings.Split(raw, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid provider override: %s", entry)
+		}
+
+		names, err := parseProviderNames(parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		overrides[stock.Symbol(strings.ToUpper(strings.TrimSpace(parts[0])))] = names
+	}
+
+	return overrides, nil
+}
+
+func parseProviderNames(raw string) ([]string, error) {
+	var names []string
+
+	for _, name := range strings.Split(raw, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name == "" {
+			return nil, fmt.Errorf("invalid provider list: %s", raw)
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"reflect"
+	"stocks/stock"
+	"testing"
+)
+
+func TestParseProviderOrder(t *testing.T) {
+	type args struct {
+		raw       string
+		priceFile string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Should use default providers when empty",
+			args: args{},
+			want: []string{"mfinance", "brapi", "yahoo"},
+		},
+		{
+			name: "Should append csv provider when price file is configured",
+			args: args{
+				priceFile: "prices.csv",
+			},
+			want: []string{"mfinance", "brapi", "yahoo", "csv"},
+		},
+		{
+			name: "Should parse configured order",
+			args: args{
+				raw: "Yahoo, mfinance",
+			},
+			want: []string{"yahoo", "mfinance"},
+		},
+		{
+			name: "Should return error for empty provider name",
+			args: args{
+				raw: "mfinance,,yahoo",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProviderOrder(tt.args.raw, tt.args.priceFile)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseProviderOrder() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseProviderOrder() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProviderOverrides(t *testing.T) {
+	type args struct {
+		raw string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[stock.Symbol][]string
+		wantErr bool
+	}{
+		{
+			name: "Should return no overrides when empty",
+			args: args{},
+			want: map[stock.Symbol][]string{},
+		},
+		{
+			name: "Should parse overrides per symbol",
+			args: args{
+				raw: "bova11=yahoo,brapi;XPML11=csv",
+			},
+			want: map[stock.Symbol][]string{
+				"BOVA11": {"yahoo", "brapi"},
+				"XPML11": {"csv"},
+			},
+		},
+		{
+			name: "Should return error for malformed override",
+			args: args{
+				raw: "BOVA11",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProviderOverrides(tt.args.raw)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseProviderOverrides() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseProviderOverrides() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
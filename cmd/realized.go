@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"stocks/date"
+	"time"
+)
+
+func CreateRealizedRequest(args ...string) (time.Time, time.Time, error) {
+	flags := flag.NewFlagSet("realized", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	rawFrom := flags.String("from", "", "first sale date")
+	rawTo := flags.String("to", "", "last sale date")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return time.Time{}, time.Time{}, errors.New("usage: stocks realized [--from <date>] [--to <date>]")
+	}
+
+	from, to := time.Time{}, time.Now().AddDate(1, 0, 0)
+
+	if *rawFrom != "" {
+		d, err := date.Parse(*rawFrom)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid from date format")
+		}
+
+		from = d
+	}
+
+	if *rawTo != "" {
+		d, err := date.Parse(*rawTo)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid to date format")
+		}
+
+		to = d.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("from date must not be after to date")
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCreateRealizedRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{
+			name: "Should create request properly with period",
+			args: args{
+				args: []string{"--from", "2023-01-01", "--to", "2023-12-31"},
+			},
+			wantFrom: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantErr:  false,
+		},
+		{
+			name: "Should return error if date is invalid",
+			args: args{
+				args: []string{"--from", "2023-13-01"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if period is inverted",
+			args: args{
+				args: []string{"--from", "2023-12-31", "--to", "2023-01-01"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if there are unexpected args",
+			args: args{
+				args: []string{"2023"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFrom, gotTo, err := CreateRealizedRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateRealizedRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotFrom, tt.wantFrom) {
+				t.Errorf("CreateRealizedRequest() gotFrom = %v, want %v", gotFrom, tt.wantFrom)
+			}
+			if !reflect.DeepEqual(gotTo, tt.wantTo) {
+				t.Errorf("CreateRealizedRequest() gotTo = %v, want %v", gotTo, tt.wantTo)
+			}
+		})
+	}
+}
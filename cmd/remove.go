@@ -0,0 +1,13 @@
+package main
+
+import (
+	"errors"
+)
+
+func CreateRemoveRequest(args ...string) (uint, error) {
+	if len(args) != 1 {
+		return 0, errors.New("usage: stocks rm <id>")
+	}
+
+	return parseID(args[0])
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCreateRemoveRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    uint
+		wantErr bool
+	}{
+		{
+			name: "Should build request properly",
+			args: args{
+				args: []string{"12"},
+			},
+			want:    12,
+			wantErr: false,
+		},
+		{
+			name: "Should return error if id is invalid",
+			args: args{
+				args: []string{"0"},
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "Should return error if is missing args",
+			args: args{
+				args: []string{},
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateRemoveRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateRemoveRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CreateRemoveRequest() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
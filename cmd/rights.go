@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"stocks/date"
+	"stocks/stock"
+	"stocks/usecase"
+	"strconv"
+)
+
+func CreateRightsRequest(args ...string) (usecase.RightsRequest, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return usecase.RightsRequest{}, errors.New("usage: stocks rights <symbol> <quantity> [<date>]")
+	}
+
+	quantity, err := strconv.Atoi(args[1])
+	if err != nil {
+		return usecase.RightsRequest{}, errors.New("invalid quantity")
+	}
+
+	rawDate := "today"
+	if len(args) == 3 {
+		rawDate = args[2]
+	}
+
+	d, err := date.Parse(rawDate)
+	if err != nil {
+		return usecase.RightsRequest{}, err
+	}
+
+	return usecase.RightsRequest{
+		Symbol:   stock.Symbol(args[0]),
+		Quantity: quantity,
+		Date:     d,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"stocks/usecase"
+	"testing"
+	"time"
+)
+
+func TestCreateRightsRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    usecase.RightsRequest
+		wantErr bool
+	}{
+		{
+			name: "Should build request properly",
+			args: args{
+				args: []string{"FUND12", "25", "2022-04-28"},
+			},
+			want: usecase.RightsRequest{
+				Symbol:   "FUND12",
+				Quantity: 25,
+				Date:     time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return error if quantity is missing",
+			args: args{
+				args: []string{"FUND12"},
+			},
+			want:    usecase.RightsRequest{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if quantity is invalid",
+			args: args{
+				args: []string{"FUND12", "abc"},
+			},
+			want:    usecase.RightsRequest{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateRightsRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateRightsRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateRightsRequest() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
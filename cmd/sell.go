@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"stocks/date"
+	"stocks/usecase"
+	"time"
+)
+
+const (
+	lotFlag = "--lot"
+)
+
+func CreateSellRequest(args ...string) (usecase.SellRequest, error) {
+	usage := "usage: stocks sell <symbol> <quantity> <unit-value> [<date> [<brokerage> <emoluments> <settlement> <iss>]] [--lot <date>]"
+
+	rawLot, rest, ok := extractFlag(lotFlag, args...)
+	if !ok {
+		return usecase.SellRequest{}, errors.New(usage)
+	}
+
+	request, err := createOperationRequest(usage, rest...)
+	if err != nil {
+		return usecase.SellRequest{}, err
+	}
+
+	var lot time.Time
+	if rawLot != "" {
+		if lot, err = date.Parse(rawLot); err != nil {
+			return usecase.SellRequest{}, errors.New("invalid lot date format")
+		}
+	}
+
+	return usecase.SellRequest{
+		Symbol:    request.Symbol,
+		Quantity:  request.Quantity,
+		UnitValue: request.UnitValue,
+		Fees:      request.Fees,
+		Date:      request.Date,
+		Lot:       lot,
+	}, nil
+}
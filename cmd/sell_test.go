@@ -0,0 +1,142 @@
+package main
+
+import (
+	"reflect"
+	"stocks/currency"
+	"stocks/date"
+	"stocks/operation"
+	"stocks/usecase"
+	"testing"
+	"time"
+)
+
+func TestCreateSellRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    usecase.SellRequest
+		wantErr bool
+	}{
+		{
+			name: "Should build request properly without data",
+			args: args{
+				args: []string{"STOCK", "10", "1.23"},
+			},
+			want: usecase.SellRequest{
+				Symbol:    "STOCK",
+				Quantity:  10,
+				UnitValue: currency.New(123),
+				Date:      date.Trunc(time.Now()),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should build request properly with data",
+			args: args{
+				args: []string{"STOCK", "10", "1.23", "2022-04-28"},
+			},
+			want: usecase.SellRequest{
+				Symbol:    "STOCK",
+				Quantity:  10,
+				UnitValue: currency.New(123),
+				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should build request properly with fees",
+			args: args{
+				args: []string{"STOCK", "10", "1.23", "2022-04-28", "4.90", "0.01", "0.03"},
+			},
+			want: usecase.SellRequest{
+				Symbol:    "STOCK",
+				Quantity:  10,
+				UnitValue: currency.New(123),
+				Fees: operation.Fees{
+					Brokerage:  currency.New(490),
+					Emoluments: currency.New(1),
+					Settlement: currency.New(3),
+				},
+				Date: time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should build request properly with lot",
+			args: args{
+				args: []string{"STOCK", "10", "1.23", "2022-04-28", "--lot", "2022-01-03"},
+			},
+			want: usecase.SellRequest{
+				Symbol:    "STOCK",
+				Quantity:  10,
+				UnitValue: currency.New(123),
+				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+				Lot:       time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return error if lot is invalid",
+			args: args{
+				args: []string{"STOCK", "10", "1.23", "--lot=2022-01"},
+			},
+			want:    usecase.SellRequest{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if is missing args",
+			args: args{
+				args: []string{"STOCK"},
+			},
+			want:    usecase.SellRequest{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if quantity is invalid",
+			args: args{
+				args: []string{"STOCK", "abc", "1.23", "2022-04-28"},
+			},
+			want:    usecase.SellRequest{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if value is invalid",
+			args: args{
+				args: []string{"STOCK", "10", "abc", "2022-04-28"},
+			},
+			want:    usecase.SellRequest{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if fees are invalid",
+			args: args{
+				args: []string{"STOCK", "10", "1.23", "2022-04-28", "abc"},
+			},
+			want:    usecase.SellRequest{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if date is invalid",
+			args: args{
+				args: []string{"STOCK", "10", "1.23", "abc"},
+			},
+			want:    usecase.SellRequest{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateSellRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateSellRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateSellRequest() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
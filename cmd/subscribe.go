@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"stocks/stock"
+	"stocks/usecase"
+)
+
+func CreateSubscribeRequest(args ...string) (usecase.SubscribeRequest, error) {
+	usage := "usage: stocks subscribe <symbol> <rights> <unit-value> [<date> [<brokerage> <emoluments> <settlement> <iss>]] [--to <symbol>]"
+
+	target, rest, ok := extractFlag(targetFlag, args...)
+	if !ok {
+		return usecase.SubscribeRequest{}, errors.New(usage)
+	}
+
+	request, err := createOperationRequest(usage, rest...)
+	if err != nil {
+		return usecase.SubscribeRequest{}, err
+	}
+
+	return usecase.SubscribeRequest{
+		Symbol:    request.Symbol,
+		Quantity:  request.Quantity,
+		UnitValue: request.UnitValue,
+		Fees:      request.Fees,
+		Date:      request.Date,
+		Target:    stock.Symbol(target),
+	}, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"stocks/currency"
+	"stocks/usecase"
+	"testing"
+	"time"
+)
+
+func TestCreateSubscribeRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    usecase.SubscribeRequest
+		wantErr bool
+	}{
+		{
+			name: "Should build request properly",
+			args: args{
+				args: []string{"FUND12", "20", "95.50", "2022-04-28"},
+			},
+			want: usecase.SubscribeRequest{
+				Symbol:    "FUND12",
+				Quantity:  20,
+				UnitValue: currency.New(9550),
+				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should build request properly with target",
+			args: args{
+				args: []string{"FUND12", "20", "95.50", "2022-04-28", "--to=FUND11"},
+			},
+			want: usecase.SubscribeRequest{
+				Symbol:    "FUND12",
+				Quantity:  20,
+				UnitValue: currency.New(9550),
+				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+				Target:    "FUND11",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return error if target is missing its value",
+			args: args{
+				args: []string{"FUND12", "20", "95.50", "--to"},
+			},
+			want:    usecase.SubscribeRequest{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateSubscribeRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateSubscribeRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateSubscribeRequest() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+func CreateTaxRequest(args ...string) (time.Time, error) {
+	if len(args) != 1 {
+		return time.Time{}, errors.New("usage: stocks tax <yyyy-mm>")
+	}
+
+	period, err := time.Parse("2006-01", args[0])
+	if err != nil {
+		return time.Time{}, errors.New("invalid period format")
+	}
+
+	return period, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCreateTaxRequest(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "Should create request properly",
+			args: args{
+				args: []string{"2022-04"},
+			},
+			want:    time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC),
+			wantErr: false,
+		},
+		{
+			name: "Should return error if period arg is missing",
+			args: args{
+				args: []string{},
+			},
+			want:    time.Time{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if period is invalid",
+			args: args{
+				args: []string{"2022-04-28"},
+			},
+			want:    time.Time{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateTaxRequest(tt.args.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateTaxRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateTaxRequest() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,13 +2,78 @@ package csv
 
 import (
 	"encoding/csv"
+	"errors"
+	"fmt"
 	"io"
+	"strconv"
+	"strings"
 )
 
+type (
+	FieldError struct {
+		Column string
+		Value  string
+		Err    error
+	}
+
+	LineError struct {
+		Line int
+		Err  error
+	}
+
+	Errors []LineError
+)
+
+func NewFieldError(column, value string, err error) FieldError {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err
+	}
+
+	return FieldError{
+		Column: column,
+		Value:  value,
+		Err:    err,
+	}
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("column %s (%q): %v", e.Column, e.Value, e.Err)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e LineError) Unwrap() error {
+	return e.Err
+}
+
+func (e Errors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 func Import[T any](reader io.Reader, hasTitle bool, fn func([]string) (T, error)) ([]T, error) {
+	output, _, err := ImportLines(reader, hasTitle, fn)
+	return output, err
+}
+
+func ImportLines[T any](reader io.Reader, hasTitle bool, fn func([]string) (T, error)) ([]T, []int, error) {
 	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
 
 	var output []T
+	var lines []int
+	var errs Errors
 	var skipped bool
 
 	for {
@@ -17,7 +82,7 @@ func Import[T any](reader io.Reader, hasTitle bool, fn func([]string) (T, error)
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if hasTitle && !skipped {
@@ -25,12 +90,18 @@ func Import[T any](reader io.Reader, hasTitle bool, fn func([]string) (T, error)
 			continue
 		}
 
+		line, _ := r.FieldPos(0)
 		if item, err := fn(record); err != nil {
-			return nil, err
+			errs = append(errs, LineError{Line: line, Err: err})
 		} else {
 			output = append(output, item)
+			lines = append(lines, line)
 		}
 	}
 
-	return output, nil
+	if len(errs) > 0 {
+		return nil, nil, errs
+	}
+
+	return output, lines, nil
 }
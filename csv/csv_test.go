@@ -0,0 +1,76 @@
+package csv
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	parse := func(elements []string) (int, error) {
+		value, err := strconv.Atoi(elements[1])
+		if err != nil {
+			return 0, NewFieldError("Value", elements[1], err)
+		}
+
+		return value, nil
+	}
+
+	type args struct {
+		raw string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []int
+		wantErr error
+	}{
+		{
+			name: "Should import every row after the title",
+			args: args{
+				raw: "Name,Value\nA,1\nB,2,extra\n",
+			},
+			want: []int{1, 2},
+		},
+		{
+			name: "Should report every invalid row with its line",
+			args: args{
+				raw: "Name,Value\nA,x\nB,2\nC,\n",
+			},
+			wantErr: Errors{
+				{Line: 2, Err: FieldError{Column: "Value", Value: "x", Err: strconv.ErrSyntax}},
+				{Line: 4, Err: FieldError{Column: "Value", Value: "", Err: strconv.ErrSyntax}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Import(strings.NewReader(tt.args.raw), true, parse)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("Import() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Import() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportLines(t *testing.T) {
+	got, lines, err := ImportLines(strings.NewReader("Name,Value\nA,1\n\"B\nC\",2\n"), true, func(elements []string) (string, error) {
+		return elements[0], nil
+	})
+	if err != nil {
+		t.Fatalf("ImportLines() error = %v", err)
+	}
+
+	if want := []string{"A", "B\nC"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ImportLines() got = %v, want %v", got, want)
+	}
+
+	if want := []int{2, 3}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("ImportLines() lines = %v, want %v", lines, want)
+	}
+}
@@ -1,31 +1,229 @@
 package currency
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
+const (
+	centsPerUnit = 100
+)
+
+var ambiguous = regexp.MustCompile(`^[1-9][0-9]{0,2}\.[0-9]{3}$`)
+
 type Currency struct {
-	value float64
+	cents int64
+}
+
+func New(cents int64) Currency {
+	return Currency{
+		cents: cents,
+	}
 }
 
 func NewFromFloat(value float64) Currency {
+	if c, err := parse(strconv.FormatFloat(value, 'f', -1, 64)); err == nil {
+		return c
+	}
+
+	return Currency{
+		cents: int64(math.Round(value * centsPerUnit)),
+	}
+}
+
+func Parse(raw string) (Currency, error) {
+	if ambiguous.MatchString(strings.Trim(raw, "-()R$ ")) {
+		return Currency{}, fmt.Errorf("ambiguous value: %s: use a comma for decimals", raw)
+	}
+
+	return parse(raw)
+}
+
+func parse(raw string) (Currency, error) {
+	value := strings.TrimSpace(raw)
+	negative := false
+
+	if strings.HasPrefix(value, "-") {
+		negative = true
+		value = strings.TrimSpace(strings.TrimPrefix(value, "-"))
+	}
+
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "("), ")")
+	}
+
+	value = strings.TrimSpace(strings.TrimPrefix(value, "R$"))
+	if value == "" {
+		return Currency{}, errors.New("empty value")
+	}
+
+	decimal := strings.LastIndexAny(value, ".,")
+	if strings.Contains(value, ".") && strings.Contains(value, ",") {
+		thousands := "."
+		if value[decimal] == '.' {
+			thousands = ","
+		}
+
+		value = strings.ReplaceAll(value, thousands, "")
+		decimal = strings.LastIndexAny(value, ".,")
+	}
+
+	integer, fraction := value, ""
+	if decimal >= 0 {
+		integer, fraction = value[:decimal], value[decimal+1:]
+	}
+
+	if integer == "" {
+		integer = "0"
+	}
+
+	units, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil || units < 0 {
+		return Currency{}, fmt.Errorf("invalid value: %s", raw)
+	}
+
+	cents := units * centsPerUnit
+	for i, digit := range fraction {
+		if digit < '0' || digit > '9' {
+			return Currency{}, fmt.Errorf("invalid value: %s", raw)
+		}
+
+		switch {
+		case i == 0:
+			cents += int64(digit-'0') * 10
+		case i == 1:
+			cents += int64(digit - '0')
+		case i == 2 && digit >= '5':
+			cents++
+		}
+	}
+
+	if negative {
+		cents = -cents
+	}
+
 	return Currency{
-		value: value,
+		cents: cents,
+	}, nil
+}
+
+func Sum(values ...Currency) Currency {
+	var sum Currency
+
+	for _, value := range values {
+		sum = sum.Add(value)
 	}
+
+	return sum
+}
+
+func (c Currency) Cents() int64 {
+	return c.cents
 }
 
 func (c Currency) Float64() float64 {
-	return c.value
+	return float64(c.cents) / centsPerUnit
+}
+
+func (c Currency) Add(other Currency) Currency {
+	return Currency{
+		cents: c.cents + other.cents,
+	}
+}
+
+func (c Currency) Sub(other Currency) Currency {
+	return Currency{
+		cents: c.cents - other.cents,
+	}
+}
+
+func (c Currency) Neg() Currency {
+	return Currency{
+		cents: -c.cents,
+	}
+}
+
+func (c Currency) Mul(quantity int) Currency {
+	return Currency{
+		cents: c.cents * int64(quantity),
+	}
+}
+
+func (c Currency) Div(quantity int) Currency {
+	return c.MulRatio(1, int64(quantity))
+}
+
+func (c Currency) MulRatio(numerator, denominator int64) Currency {
+	if denominator == 0 {
+		return Currency{}
+	}
+
+	product := c.cents * numerator
+	quotient, remainder := product/denominator, product%denominator
+
+	if remainder != 0 && 2*abs(remainder) >= abs(denominator) {
+		if (product < 0) != (denominator < 0) {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+
+	return Currency{
+		cents: quotient,
+	}
+}
+
+func (c Currency) Cmp(other Currency) int {
+	switch {
+	case c.cents < other.cents:
+		return -1
+	case c.cents > other.cents:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (c Currency) IsZero() bool {
+	return c.cents == 0
+}
+
+func (c Currency) IsNegative() bool {
+	return c.cents < 0
+}
+
+func (c Currency) IsPositive() bool {
+	return c.cents > 0
+}
+
+func (c Currency) Decimal() string {
+	sign := ""
+	if c.cents < 0 {
+		sign = "-"
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, abs(c.cents)/centsPerUnit, abs(c.cents)%centsPerUnit)
 }
 
 func (c Currency) String() string {
-	raw := fmt.Sprintf("%.2f", math.Abs(c.value))
-	raw = strings.ReplaceAll(raw, ".", ",")
+	raw := strings.ReplaceAll(Currency{cents: abs(c.cents)}.Decimal(), ".", ",")
 
-	if c.value < 0 {
+	if c.cents < 0 {
 		return fmt.Sprintf("-(R$ %s)", raw)
 	}
 	return fmt.Sprintf("R$ %s", raw)
 }
+
+func abs(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
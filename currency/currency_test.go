@@ -7,7 +7,7 @@ import (
 
 func TestCurrency_Float64(t *testing.T) {
 	type fields struct {
-		value float64
+		cents int64
 	}
 	tests := []struct {
 		name   string
@@ -17,7 +17,7 @@ func TestCurrency_Float64(t *testing.T) {
 		{
 			name: "Should return float value",
 			fields: fields{
-				value: 1.23,
+				cents: 123,
 			},
 			want: 1.23,
 		},
@@ -25,7 +25,7 @@ func TestCurrency_Float64(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := Currency{
-				value: tt.fields.value,
+				cents: tt.fields.cents,
 			}
 			if got := c.Float64(); got != tt.want {
 				t.Errorf("Float64() = %v, want %v", got, tt.want)
@@ -36,7 +36,7 @@ func TestCurrency_Float64(t *testing.T) {
 
 func TestCurrency_String(t *testing.T) {
 	type fields struct {
-		value float64
+		cents int64
 	}
 	tests := []struct {
 		name   string
@@ -46,22 +46,29 @@ func TestCurrency_String(t *testing.T) {
 		{
 			name: "Should print positive values properly",
 			fields: fields{
-				value: -1.23,
+				cents: -123,
 			},
 			want: "-(R$ 1,23)",
 		},
 		{
 			name: "Should print negative values properly",
 			fields: fields{
-				value: 1.23,
+				cents: 123,
 			},
 			want: "R$ 1,23",
 		},
+		{
+			name: "Should print values with leading zero cents properly",
+			fields: fields{
+				cents: 100005,
+			},
+			want: "R$ 1000,05",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := Currency{
-				value: tt.fields.value,
+				cents: tt.fields.cents,
 			}
 			if got := c.String(); got != tt.want {
 				t.Errorf("String() = %v, want %v", got, tt.want)
@@ -70,6 +77,110 @@ func TestCurrency_String(t *testing.T) {
 	}
 }
 
+func TestCurrency_Decimal(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Currency
+		want string
+	}{
+		{
+			name: "Should format positive values",
+			c:    New(123456),
+			want: "1234.56",
+		},
+		{
+			name: "Should format negative values",
+			c:    New(-5),
+			want: "-0.05",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Decimal(); got != tt.want {
+				t.Errorf("Decimal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrency_MulRatio(t *testing.T) {
+	type args struct {
+		numerator   int64
+		denominator int64
+	}
+	tests := []struct {
+		name string
+		c    Currency
+		args args
+		want Currency
+	}{
+		{
+			name: "Should round half up",
+			c:    New(100),
+			args: args{
+				numerator:   1,
+				denominator: 8,
+			},
+			want: New(13),
+		},
+		{
+			name: "Should round down below half",
+			c:    New(100),
+			args: args{
+				numerator:   1,
+				denominator: 3,
+			},
+			want: New(33),
+		},
+		{
+			name: "Should round negative values away from zero",
+			c:    New(-100),
+			args: args{
+				numerator:   1,
+				denominator: 8,
+			},
+			want: New(-13),
+		},
+		{
+			name: "Should return zero for zero denominator",
+			c:    New(100),
+			args: args{
+				numerator:   1,
+				denominator: 0,
+			},
+			want: New(0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.MulRatio(tt.args.numerator, tt.args.denominator); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MulRatio() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []Currency
+		want   Currency
+	}{
+		{
+			name:   "Should sum values without drifting",
+			values: []Currency{NewFromFloat(0.1), NewFromFloat(0.2), NewFromFloat(-0.3)},
+			want:   New(0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sum(tt.values...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestNewFromFloat(t *testing.T) {
 	type args struct {
 		value float64
@@ -85,7 +196,25 @@ func TestNewFromFloat(t *testing.T) {
 				value: 1.23,
 			},
 			want: Currency{
-				value: 1.23,
+				cents: 123,
+			},
+		},
+		{
+			name: "Should round to the nearest cent",
+			args: args{
+				value: 1.005,
+			},
+			want: Currency{
+				cents: 101,
+			},
+		},
+		{
+			name: "Should round floats with three decimal places",
+			args: args{
+				value: 1.235,
+			},
+			want: Currency{
+				cents: 124,
 			},
 		},
 	}
@@ -97,3 +226,98 @@ func TestNewFromFloat(t *testing.T) {
 		})
 	}
 }
+
+func TestParse(t *testing.T) {
+	type args struct {
+		raw string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Currency
+		wantErr bool
+	}{
+		{
+			name: "Should parse dot decimal values",
+			args: args{
+				raw: "1234.56",
+			},
+			want: New(123456),
+		},
+		{
+			name: "Should parse comma decimal values",
+			args: args{
+				raw: "1234,5",
+			},
+			want: New(123450),
+		},
+		{
+			name: "Should parse formatted values",
+			args: args{
+				raw: "R$ 1.234,56",
+			},
+			want: New(123456),
+		},
+		{
+			name: "Should parse negative formatted values",
+			args: args{
+				raw: "-(R$ 4,00)",
+			},
+			want: New(-400),
+		},
+		{
+			name: "Should round extra decimal places",
+			args: args{
+				raw: "0.125",
+			},
+			want: New(13),
+		},
+		{
+			name: "Should round extra decimal places of large values",
+			args: args{
+				raw: "1234.567",
+			},
+			want: New(123457),
+		},
+		{
+			name: "Should return error for a dot followed by three digits",
+			args: args{
+				raw: "R$ 1.234",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should parse integer values",
+			args: args{
+				raw: "12",
+			},
+			want: New(1200),
+		},
+		{
+			name: "Should return error for invalid values",
+			args: args{
+				raw: "abc",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error for empty values",
+			args: args{
+				raw: "",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args.raw)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
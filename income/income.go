@@ -0,0 +1,271 @@
+package income
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"stocks/csv"
+	"stocks/currency"
+	"stocks/separator"
+	"stocks/stock"
+	"time"
+)
+
+const (
+	Dividend Type = iota
+	InterestOnEquity
+	FundIncome
+
+	InterestOnEquityWithholdingRate = 15
+
+	dateLayout  = "2006-01-02"
+	monthLayout = "2006-01"
+)
+
+var (
+	columns = []string{"Symbol", "Type", "Date", "Amount", "IRRF", "Portfolio"}
+)
+
+type (
+	Type int
+
+	Repository interface {
+		InsertIncome(ctx context.Context, income Income) error
+		InsertIncomes(ctx context.Context, incomes List) error
+		Incomes(ctx context.Context) (List, error)
+	}
+
+	Income struct {
+		Symbol      stock.Symbol
+		Type        Type
+		Amount      currency.Currency
+		WithheldTax currency.Currency
+		Date        time.Time
+		Portfolio   string
+	}
+
+	List []Income
+
+	Entry struct {
+		Month       time.Time
+		Symbol      stock.Symbol
+		Amounts     map[Type]currency.Currency
+		WithheldTax currency.Currency
+	}
+
+	Report []Entry
+)
+
+func (t Type) String() string {
+	switch t {
+	case Dividend:
+		return "DIVIDEND"
+	case InterestOnEquity:
+		return "JCP"
+	case FundIncome:
+		return "FII"
+	default:
+		return ""
+	}
+}
+
+func (i Income) Net() currency.Currency {
+	return i.Amount.Sub(i.WithheldTax)
+}
+
+func (i Income) key() string {
+	return fmt.Sprintf("%s|%s|%s|%d|%s", i.Symbol, i.Type, i.Date.Format(dateLayout), i.Amount.Cents(), i.Portfolio)
+}
+
+func (l List) Of(symbol stock.Symbol) List {
+	var output List
+
+	for _, income := range l {
+		if income.Symbol == symbol {
+			output = append(output, income)
+		}
+	}
+
+	return output
+}
+
+func (l List) In(portfolio string) List {
+	if portfolio == "" {
+		return l
+	}
+
+	var output List
+	for _, income := range l {
+		if income.Portfolio == portfolio {
+			output = append(output, income)
+		}
+	}
+
+	return output
+}
+
+func (l List) Missing(existing List) List {
+	stored := map[string]int{}
+	for _, income := range existing {
+		stored[income.key()]++
+	}
+
+	var output List
+	for _, income := range l {
+		if key := income.key(); stored[key] > 0 {
+			stored[key]--
+		} else {
+			output = append(output, income)
+		}
+	}
+
+	return output
+}
+
+func (l List) Between(from, to time.Time) List {
+	var output List
+
+	for _, income := range l {
+		if !income.Date.Before(from) && income.Date.Before(to) {
+			output = append(output, income)
+		}
+	}
+
+	return output
+}
+
+func (l List) Net() currency.Currency {
+	var net currency.Currency
+
+	for _, income := range l {
+		net = net.Add(income.Net())
+	}
+
+	return net
+}
+
+func (l List) Report() Report {
+	var report Report
+	index := map[string]int{}
+
+	for _, income := range l {
+		month := time.Date(income.Date.Year(), income.Date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		key := fmt.Sprintf("%s/%s", month.Format(monthLayout), income.Symbol)
+
+		i, ok := index[key]
+		if !ok {
+			i = len(report)
+			index[key] = i
+			report = append(report, Entry{
+				Month:   month,
+				Symbol:  income.Symbol,
+				Amounts: map[Type]currency.Currency{},
+			})
+		}
+
+		report[i].Amounts[income.Type] = report[i].Amounts[income.Type].Add(income.Amount)
+		report[i].WithheldTax = report[i].WithheldTax.Add(income.WithheldTax)
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Month.Equal(report[j].Month) {
+			return report[i].Symbol < report[j].Symbol
+		}
+
+		return report[i].Month.Before(report[j].Month)
+	})
+
+	return report
+}
+
+func (e Entry) Net() currency.Currency {
+	net := e.WithheldTax.Neg()
+
+	for _, amount := range e.Amounts {
+		net = net.Add(amount)
+	}
+
+	return net
+}
+
+func (r Report) Print(writer io.Writer, sep separator.Separator) error {
+	title := fmt.Sprintf("Month%sSymbol%sDividends%sJCP%sFII%sIRRF%sNet\n", sep, sep, sep, sep, sep, sep)
+	if _, err := io.WriteString(writer, title); err != nil {
+		return err
+	}
+
+	for _, entry := range r {
+		line := fmt.Sprintf("%s%s%s%s%s%s%s%s%s%s%s%s%s\n",
+			entry.Month.Format(monthLayout), sep, entry.Symbol, sep,
+			entry.Amounts[Dividend], sep, entry.Amounts[InterestOnEquity], sep, entry.Amounts[FundIncome], sep,
+			entry.WithheldTax, sep, entry.Net())
+
+		if _, err := io.WriteString(writer, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r Report) Net() currency.Currency {
+	var net currency.Currency
+
+	for _, entry := range r {
+		net = net.Add(entry.Net())
+	}
+
+	return net
+}
+
+func ParseFromCSV(elements []string) (Income, error) {
+	if len(elements) < 4 {
+		return Income{}, fmt.Errorf("expected at least 4 columns, got %d", len(elements))
+	}
+
+	types := map[string]Type{
+		"DIVIDEND": Dividend,
+		"JCP":      InterestOnEquity,
+		"FII":      FundIncome,
+	}
+
+	t, ok := types[elements[1]]
+	if !ok {
+		return Income{}, csv.NewFieldError(columns[1], elements[1], errors.New("unknown income type"))
+	}
+
+	date, err := time.Parse(dateLayout, elements[2])
+	if err != nil {
+		return Income{}, csv.NewFieldError(columns[2], elements[2], errors.New("expected YYYY-MM-DD"))
+	}
+
+	amount, err := currency.Parse(elements[3])
+	if err != nil {
+		return Income{}, csv.NewFieldError(columns[3], elements[3], err)
+	}
+
+	var withheldTax currency.Currency
+	if len(elements) > 4 && elements[4] != "" {
+		if withheldTax, err = currency.Parse(elements[4]); err != nil {
+			return Income{}, csv.NewFieldError(columns[4], elements[4], err)
+		}
+	} else if t == InterestOnEquity {
+		withheldTax = amount.MulRatio(InterestOnEquityWithholdingRate, 100)
+	}
+
+	var portfolio string
+	if len(elements) > 5 {
+		portfolio = elements[5]
+	}
+
+	return Income{
+		Symbol:      stock.Symbol(elements[0]),
+		Type:        t,
+		Amount:      amount,
+		WithheldTax: withheldTax,
+		Date:        date,
+		Portfolio:   portfolio,
+	}, nil
+}
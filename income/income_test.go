@@ -0,0 +1,135 @@
+package income
+
+import (
+	"bytes"
+	"reflect"
+	"stocks/currency"
+	"stocks/separator"
+	"testing"
+	"time"
+)
+
+func TestParseFromCSV(t *testing.T) {
+	type args struct {
+		elements []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Income
+		wantErr bool
+	}{
+		{
+			name: "Should parse dividend properly",
+			args: args{
+				elements: []string{"STOCK1", "DIVIDEND", "2022-04-28", "12.34"},
+			},
+			want: Income{
+				Symbol: "STOCK1",
+				Type:   Dividend,
+				Amount: currency.NewFromFloat(12.34),
+				Date:   time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Should apply default withholding for interest on equity",
+			args: args{
+				elements: []string{"STOCK1", "JCP", "2022-04-28", "100"},
+			},
+			want: Income{
+				Symbol:      "STOCK1",
+				Type:        InterestOnEquity,
+				Amount:      currency.NewFromFloat(100),
+				WithheldTax: currency.NewFromFloat(15),
+				Date:        time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Should use informed withholding",
+			args: args{
+				elements: []string{"STOCK1", "JCP", "2022-04-28", "100", "0"},
+			},
+			want: Income{
+				Symbol: "STOCK1",
+				Type:   InterestOnEquity,
+				Amount: currency.NewFromFloat(100),
+				Date:   time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Should parse portfolio",
+			args: args{
+				elements: []string{"STOCK1", "DIVIDEND", "2022-04-28", "12.34", "", "xp"},
+			},
+			want: Income{
+				Symbol:    "STOCK1",
+				Type:      Dividend,
+				Amount:    currency.NewFromFloat(12.34),
+				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+				Portfolio: "xp",
+			},
+		},
+		{
+			name: "Should return error if type is invalid",
+			args: args{
+				elements: []string{"STOCK1", "BUY", "2022-04-28", "100"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if amount is invalid",
+			args: args{
+				elements: []string{"STOCK1", "FII", "2022-04-28", "abc"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFromCSV(tt.args.elements)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFromCSV() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFromCSV() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReport_Print(t *testing.T) {
+	tests := []struct {
+		name       string
+		incomes    List
+		wantWriter string
+		wantErr    bool
+	}{
+		{
+			name: "Should print income report grouped by month and symbol",
+			incomes: List{
+				{Symbol: "STOCK2", Type: Dividend, Amount: currency.NewFromFloat(10), Date: time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC)},
+				{Symbol: "STOCK1", Type: InterestOnEquity, Amount: currency.NewFromFloat(100), WithheldTax: currency.NewFromFloat(15), Date: time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC)},
+				{Symbol: "STOCK1", Type: Dividend, Amount: currency.NewFromFloat(20), Date: time.Date(2022, 4, 20, 0, 0, 0, 0, time.UTC)},
+				{Symbol: "FUND11", Type: FundIncome, Amount: currency.NewFromFloat(5), Date: time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)},
+			},
+			wantWriter: "Month,Symbol,Dividends,JCP,FII,IRRF,Net\n" +
+				"2022-03,FUND11,R$ 0,00,R$ 0,00,R$ 5,00,R$ 0,00,R$ 5,00\n" +
+				"2022-04,STOCK1,R$ 20,00,R$ 100,00,R$ 0,00,R$ 15,00,R$ 105,00\n" +
+				"2022-04,STOCK2,R$ 10,00,R$ 0,00,R$ 0,00,R$ 0,00,R$ 10,00\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			err := tt.incomes.Report().Print(writer, separator.Comma)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Print() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("Print() gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package b3
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	stockscsv "stocks/csv"
+	"stocks/currency"
+	"stocks/income"
+	"stocks/operation"
+	"stocks/stock"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout = "02/01/2006"
+
+	tradeDate       = "data do negocio"
+	tradeSide       = "tipo de movimentacao"
+	tradeMarket     = "mercado"
+	tradeBroker     = "instituicao"
+	tradeSymbol     = "codigo de negociacao"
+	tradeQuantity   = "quantidade"
+	tradePrice      = "preco"
+	movementFlow    = "entrada/saida"
+	movementDate    = "data"
+	movementKind    = "movimentacao"
+	movementProduct = "produto"
+	movementBroker  = "instituicao"
+	movementQty     = "quantidade"
+	movementPrice   = "preco unitario"
+	movementValue   = "valor da operacao"
+
+	credit = "credito"
+)
+
+var (
+	accents = strings.NewReplacer("á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+		"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ç", "c")
+
+	spotMarkets = map[string]bool{
+		"mercado a vista":     true,
+		"mercado fracionario": true,
+	}
+
+	incomeTypes = map[string]income.Type{
+		"dividendo":                   income.Dividend,
+		"juros sobre capital proprio": income.InterestOnEquity,
+		"rendimento":                  income.FundIncome,
+	}
+
+	eventTypes = map[string]operation.Type{
+		"desdobro":              operation.Split,
+		"grupamento":            operation.ReverseSplit,
+		"bonificacao em ativos": operation.Bonus,
+	}
+
+	subscriptionTypes = map[string]operation.Type{
+		"direito de subscricao": operation.Right,
+		"recibo de subscricao":  operation.Subscription,
+	}
+)
+
+type (
+	Statement struct {
+		Operations operation.List
+		Incomes    income.List
+		Ignored    map[string]int
+	}
+
+	Resolver func(broker string) string
+
+	sheet struct {
+		header map[string]int
+		rows   [][]string
+		line   int
+	}
+)
+
+func Parse(data []byte, portfolioOf Resolver) (Statement, error) {
+	rows, err := read(data)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	s, err := newSheet(rows)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	switch {
+	case s.has(tradeSymbol, tradeSide):
+		return s.trades(portfolioOf)
+	case s.has(movementFlow, movementKind, movementProduct):
+		return s.movements(portfolioOf)
+	default:
+		return Statement{}, errors.New("unrecognized B3 statement: expected negociação or movimentação columns")
+	}
+}
+
+func read(data []byte) ([][]string, error) {
+	if bytes.HasPrefix(data, []byte("PK")) {
+		return readXLSX(data)
+	}
+
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	return reader.ReadAll()
+}
+
+func newSheet(rows [][]string) (sheet, error) {
+	for i, row := range rows {
+		header := map[string]int{}
+		for j, column := range row {
+			if name := normalize(column); name != "" {
+				header[name] = j
+			}
+		}
+
+		if len(header) > 1 {
+			return sheet{header: header, rows: rows[i+1:], line: i + 2}, nil
+		}
+	}
+
+	return sheet{}, errors.New("empty statement")
+}
+
+func (s sheet) has(columns ...string) bool {
+	for _, column := range columns {
+		if _, ok := s.header[column]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s sheet) value(row []string, column string) string {
+	if i, ok := s.header[column]; ok && i < len(row) {
+		return strings.TrimSpace(row[i])
+	}
+
+	return ""
+}
+
+func (s sheet) trades(portfolioOf Resolver) (Statement, error) {
+	statement := Statement{Ignored: map[string]int{}}
+	var errs stockscsv.Errors
+
+	for i, row := range s.rows {
+		if blank(row) {
+			continue
+		}
+
+		if market := normalize(s.value(row, tradeMarket)); market != "" && !spotMarkets[market] {
+			statement.Ignored[s.value(row, tradeMarket)]++
+			continue
+		}
+
+		op, err := s.trade(row, portfolioOf)
+		if err != nil {
+			errs = append(errs, stockscsv.LineError{Line: s.line + i, Err: err})
+			continue
+		}
+
+		statement.Operations = append(statement.Operations, op)
+	}
+
+	if len(errs) > 0 {
+		return Statement{}, errs
+	}
+
+	return statement, nil
+}
+
+func (s sheet) trade(row []string, portfolioOf Resolver) (operation.Operation, error) {
+	var t operation.Type
+	switch side := s.value(row, tradeSide); normalize(side) {
+	case "compra":
+		t = operation.Buy
+	case "venda":
+		t = operation.Sell
+	default:
+		return operation.Operation{}, stockscsv.NewFieldError("Tipo de Movimentação", side, errors.New("expected Compra or Venda"))
+	}
+
+	date, err := parseDate(s.value(row, tradeDate))
+	if err != nil {
+		return operation.Operation{}, stockscsv.NewFieldError("Data do Negócio", s.value(row, tradeDate), err)
+	}
+
+	quantity, err := parseQuantity(s.value(row, tradeQuantity))
+	if err != nil || quantity <= 0 {
+		return operation.Operation{}, stockscsv.NewFieldError("Quantidade", s.value(row, tradeQuantity), errors.New("must be a positive integer"))
+	}
+
+	price, err := parseValue(s.value(row, tradePrice))
+	if err != nil {
+		return operation.Operation{}, stockscsv.NewFieldError("Preço", s.value(row, tradePrice), err)
+	}
+
+	symbol := s.value(row, tradeSymbol)
+	if symbol == "" {
+		return operation.Operation{}, stockscsv.NewFieldError("Código de Negociação", symbol, errors.New("missing symbol"))
+	}
+
+	return operation.Operation{
+		Symbol:    spotSymbol(symbol),
+		Type:      t,
+		Quantity:  quantity,
+		UnitValue: price,
+		Date:      date,
+		Portfolio: portfolioOf(s.value(row, tradeBroker)),
+	}, nil
+}
+
+func (s sheet) movements(portfolioOf Resolver) (Statement, error) {
+	statement := Statement{Ignored: map[string]int{}}
+	events := map[string]int{}
+	var errs stockscsv.Errors
+
+	for i, row := range s.rows {
+		if blank(row) {
+			continue
+		}
+
+		kind := s.value(row, movementKind)
+		incomeType, isIncome := incomeTypes[normalize(kind)]
+		eventType, isEvent := eventTypes[normalize(kind)]
+		subscriptionType, isSubscription := subscriptionTypes[normalize(kind)]
+
+		if normalize(s.value(row, movementFlow)) != credit || (!isIncome && !isEvent && !isSubscription) {
+			statement.Ignored[kind]++
+			continue
+		}
+
+		var err error
+		var op operation.Operation
+		switch {
+		case isIncome:
+			var i income.Income
+			if i, err = s.income(row, incomeType, portfolioOf); err == nil {
+				statement.Incomes = append(statement.Incomes, i)
+			}
+		case isEvent:
+			if op, err = s.event(row, eventType); err == nil {
+				key := fmt.Sprintf("%s|%s|%s", op.Symbol, op.Type, op.Date.Format(dateLayout))
+				if n, ok := events[key]; ok {
+					statement.Operations[n].Quantity += op.Quantity
+				} else {
+					events[key] = len(statement.Operations)
+					statement.Operations = append(statement.Operations, op)
+				}
+			}
+		default:
+			if op, err = s.subscription(row, subscriptionType, portfolioOf); err == nil {
+				statement.Operations = append(statement.Operations, op)
+			}
+		}
+
+		if err != nil {
+			errs = append(errs, stockscsv.LineError{Line: s.line + i, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return Statement{}, errs
+	}
+
+	return statement, nil
+}
+
+func (s sheet) income(row []string, t income.Type, portfolioOf Resolver) (income.Income, error) {
+	date, err := parseDate(s.value(row, movementDate))
+	if err != nil {
+		return income.Income{}, stockscsv.NewFieldError("Data", s.value(row, movementDate), err)
+	}
+
+	value, err := parseValue(s.value(row, movementValue))
+	if err != nil {
+		return income.Income{}, stockscsv.NewFieldError("Valor da Operação", s.value(row, movementValue), err)
+	}
+
+	return newIncome(s.product(row), t, value, date, portfolioOf(s.value(row, movementBroker))), nil
+}
+
+func (s sheet) event(row []string, t operation.Type) (operation.Operation, error) {
+	date, err := parseDate(s.value(row, movementDate))
+	if err != nil {
+		return operation.Operation{}, stockscsv.NewFieldError("Data", s.value(row, movementDate), err)
+	}
+
+	quantity, err := parseQuantity(s.value(row, movementQty))
+	if err != nil || quantity <= 0 {
+		return operation.Operation{}, stockscsv.NewFieldError("Quantidade", s.value(row, movementQty), errors.New("must be a positive integer"))
+	}
+
+	price, err := parseValue(s.value(row, movementPrice))
+	if err != nil {
+		return operation.Operation{}, stockscsv.NewFieldError("Preço unitário", s.value(row, movementPrice), err)
+	}
+
+	return operation.Operation{
+		Symbol:    s.product(row),
+		Type:      t,
+		Quantity:  quantity,
+		UnitValue: price,
+		Date:      date,
+	}, nil
+}
+
+func (s sheet) subscription(row []string, t operation.Type, portfolioOf Resolver) (operation.Operation, error) {
+	op, err := s.event(row, t)
+	if err != nil {
+		return operation.Operation{}, err
+	}
+
+	op.Portfolio = portfolioOf(s.value(row, movementBroker))
+
+	switch t {
+	case operation.Right:
+		op.UnitValue = currency.Currency{}
+	case operation.Subscription:
+		if op.Target, op.Symbol = op.Symbol, op.Symbol.Right(); op.Symbol == "" {
+			return operation.Operation{}, stockscsv.NewFieldError("Produto", s.value(row, movementProduct),
+				errors.New("unknown subscription receipt"))
+		}
+	}
+
+	return op, nil
+}
+
+func (s sheet) product(row []string) stock.Symbol {
+	symbol, _, _ := strings.Cut(s.value(row, movementProduct), " - ")
+	return stock.Symbol(strings.TrimSpace(symbol))
+}
+
+func newIncome(symbol stock.Symbol, t income.Type, value currency.Currency, date time.Time, portfolio string) income.Income {
+	i := income.Income{
+		Symbol:    symbol,
+		Type:      t,
+		Amount:    value,
+		Date:      date,
+		Portfolio: portfolio,
+	}
+
+	if t == income.InterestOnEquity {
+		i.Amount = value.MulRatio(100, 100-income.InterestOnEquityWithholdingRate)
+		i.WithheldTax = i.Amount.Sub(value)
+	}
+
+	return i
+}
+
+func spotSymbol(symbol string) stock.Symbol {
+	if len(symbol) > 5 && strings.HasSuffix(symbol, "F") {
+		symbol = strings.TrimSuffix(symbol, "F")
+	}
+
+	return stock.Symbol(symbol)
+}
+
+func parseDate(raw string) (time.Time, error) {
+	if date, err := time.Parse(dateLayout, raw); err == nil {
+		return date, nil
+	}
+
+	serial, err := strconv.ParseFloat(raw, 64)
+	if err != nil || serial <= 0 {
+		return time.Time{}, errors.New("expected DD/MM/YYYY")
+	}
+
+	return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial)), nil
+}
+
+func parseQuantity(raw string) (int, error) {
+	value, err := strconv.ParseFloat(strings.ReplaceAll(raw, ",", "."), 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if value != math.Trunc(value) {
+		return 0, fmt.Errorf("fractional quantity %s", raw)
+	}
+
+	return int(value), nil
+}
+
+func parseValue(raw string) (currency.Currency, error) {
+	if raw == "" || raw == "-" {
+		return currency.Currency{}, nil
+	}
+
+	return currency.Parse(raw)
+}
+
+func normalize(raw string) string {
+	return accents.Replace(strings.ToLower(strings.TrimSpace(raw)))
+}
+
+func blank(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,170 @@
+package b3
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"reflect"
+	"stocks/currency"
+	"stocks/income"
+	"stocks/operation"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	portfolioOf := func(broker string) string {
+		if broker == "XP INVESTIMENTOS CCTVM S/A" {
+			return "xp"
+		}
+
+		return "default"
+	}
+
+	type args struct {
+		data []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Statement
+		wantErr bool
+	}{
+		{
+			name: "Should parse negotiation statement",
+			args: args{
+				data: []byte("Data do Negócio;Tipo de Movimentação;Mercado;Prazo/Vencimento;Instituição;Código de Negociação;Quantidade;Preço;Valor\n" +
+					"02/05/2022;Compra;Mercado à Vista;-;XP INVESTIMENTOS CCTVM S/A;PETR4;100;R$ 33,50;R$ 3.350,00\n" +
+					"03/05/2022;Venda;Mercado Fracionário;-;CLEAR CORRETORA;ITSA4F;7;R$ 9,10;R$ 63,70\n" +
+					"04/05/2022;Compra;Opção de Compra;20/05/2022;CLEAR CORRETORA;PETRE350;100;R$ 0,50;R$ 50,00\n"),
+			},
+			want: Statement{
+				Operations: operation.List{
+					{Symbol: "PETR4", Type: operation.Buy, Quantity: 100, UnitValue: currency.New(3350), Date: time.Date(2022, 5, 2, 0, 0, 0, 0, time.UTC), Portfolio: "xp"},
+					{Symbol: "ITSA4", Type: operation.Sell, Quantity: 7, UnitValue: currency.New(910), Date: time.Date(2022, 5, 3, 0, 0, 0, 0, time.UTC), Portfolio: "default"},
+				},
+				Ignored: map[string]int{"Opção de Compra": 1},
+			},
+		},
+		{
+			name: "Should parse movement statement",
+			args: args{
+				data: []byte("Entrada/Saída,Data,Movimentação,Produto,Instituição,Quantidade,Preço unitário,Valor da Operação\n" +
+					"Credito,10/05/2022,Dividendo,ITSA4 - ITAUSA S/A,CLEAR CORRETORA,100,\"0,02\",\"2,00\"\n" +
+					"Credito,11/05/2022,Juros Sobre Capital Próprio,ITSA4 - ITAUSA S/A,CLEAR CORRETORA,100,\"0,0085\",\"0,85\"\n" +
+					"Credito,12/05/2022,Rendimento,HGLG11 - CSHG LOGISTICA,XP INVESTIMENTOS CCTVM S/A,10,\"1,10\",\"11,00\"\n" +
+					"Credito,13/05/2022,Bonificação em Ativos,ITSA4 - ITAUSA S/A,CLEAR CORRETORA,10,\"18,40\",-\n" +
+					"Credito,14/05/2022,Transferência - Liquidação,PETR4 - PETROBRAS,XP INVESTIMENTOS CCTVM S/A,100,\"33,50\",\"3.350,00\"\n" +
+					"Credito,16/05/2022,Direito de Subscrição,HGLG12 - CSHG LOGISTICA,XP INVESTIMENTOS CCTVM S/A,3,-,-\n" +
+					"Credito,20/05/2022,Recibo de Subscrição,HGLG13 - CSHG LOGISTICA,XP INVESTIMENTOS CCTVM S/A,3,\"150,00\",\"450,00\"\n"),
+			},
+			want: Statement{
+				Operations: operation.List{
+					{Symbol: "ITSA4", Type: operation.Bonus, Quantity: 10, UnitValue: currency.New(1840), Date: time.Date(2022, 5, 13, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "HGLG12", Type: operation.Right, Quantity: 3, Date: time.Date(2022, 5, 16, 0, 0, 0, 0, time.UTC), Portfolio: "xp"},
+					{Symbol: "HGLG12", Type: operation.Subscription, Quantity: 3, UnitValue: currency.New(15000), Date: time.Date(2022, 5, 20, 0, 0, 0, 0, time.UTC), Portfolio: "xp", Target: "HGLG13"},
+				},
+				Incomes: income.List{
+					{Symbol: "ITSA4", Type: income.Dividend, Amount: currency.New(200), Date: time.Date(2022, 5, 10, 0, 0, 0, 0, time.UTC), Portfolio: "default"},
+					{Symbol: "ITSA4", Type: income.InterestOnEquity, Amount: currency.New(100), WithheldTax: currency.New(15), Date: time.Date(2022, 5, 11, 0, 0, 0, 0, time.UTC), Portfolio: "default"},
+					{Symbol: "HGLG11", Type: income.FundIncome, Amount: currency.New(1100), Date: time.Date(2022, 5, 12, 0, 0, 0, 0, time.UTC), Portfolio: "xp"},
+				},
+				Ignored: map[string]int{"Transferência - Liquidação": 1},
+			},
+		},
+		{
+			name: "Should merge event rows of every brokerage into a single event",
+			args: args{
+				data: []byte("Entrada/Saída,Data,Movimentação,Produto,Instituição,Quantidade,Preço unitário,Valor da Operação\n" +
+					"Credito,13/05/2022,Desdobro,ITSA4 - ITAUSA S/A,CLEAR CORRETORA,50,-,-\n" +
+					"Credito,13/05/2022,Desdobro,ITSA4 - ITAUSA S/A,XP INVESTIMENTOS CCTVM S/A,50,-,-\n"),
+			},
+			want: Statement{
+				Operations: operation.List{
+					{Symbol: "ITSA4", Type: operation.Split, Quantity: 100, Date: time.Date(2022, 5, 13, 0, 0, 0, 0, time.UTC)},
+				},
+				Ignored: map[string]int{},
+			},
+		},
+		{
+			name: "Should parse negotiation spreadsheet",
+			args: args{
+				data: spreadsheet(t,
+					[]string{"Data do Negócio", "Tipo de Movimentação", "Mercado", "Instituição", "Código de Negociação", "Quantidade", "Preço"},
+					[]string{"02/05/2022", "Compra", "Mercado à Vista", "XP INVESTIMENTOS CCTVM S/A", "PETR4", "100", "33.5"}),
+			},
+			want: Statement{
+				Operations: operation.List{
+					{Symbol: "PETR4", Type: operation.Buy, Quantity: 100, UnitValue: currency.New(3350), Date: time.Date(2022, 5, 2, 0, 0, 0, 0, time.UTC), Portfolio: "xp"},
+				},
+				Ignored: map[string]int{},
+			},
+		},
+		{
+			name: "Should return error for invalid rows",
+			args: args{
+				data: []byte("Data do Negócio;Tipo de Movimentação;Código de Negociação;Quantidade;Preço\n" +
+					"02/05/2022;Aluguel;PETR4;100;33,50\n"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error for unknown statements",
+			args: args{
+				data: []byte("Symbol,Type,Qtd\nPETR4,BUY,100\n"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args.data, portfolioOf)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func spreadsheet(t *testing.T, rows ...[]string) []byte {
+	sheet := &bytes.Buffer{}
+	shared := &bytes.Buffer{}
+
+	index := 0
+	sheet.WriteString("<worksheet><sheetData>")
+	shared.WriteString("<sst>")
+	for i, row := range rows {
+		sheet.WriteString("<row>")
+		for j, value := range row {
+			fmt.Fprintf(sheet, `<c r="%c%d" t="s"><v>%d</v></c>`, 'A'+j, i+1, index)
+			fmt.Fprintf(shared, "<si><t>%s</t></si>", value)
+			index++
+		}
+		sheet.WriteString("</row>")
+	}
+	sheet.WriteString("</sheetData></worksheet>")
+	shared.WriteString("</sst>")
+
+	buffer := &bytes.Buffer{}
+	archive := zip.NewWriter(buffer)
+	for name, content := range map[string]*bytes.Buffer{"xl/worksheets/sheet1.xml": sheet, "xl/sharedStrings.xml": shared} {
+		writer, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := writer.Write(content.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,190 @@
+package b3
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	workbookPath      = "xl/workbook.xml"
+	workbookRelsPath  = "xl/_rels/workbook.xml.rels"
+	sharedStringsPath = "xl/sharedStrings.xml"
+)
+
+type (
+	sharedStrings struct {
+		Items []struct {
+			Text string `xml:"t"`
+			Runs []struct {
+				Text string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+
+	workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+
+	relationships struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+
+	worksheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline struct {
+					Text string `xml:"t"`
+				} `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+)
+
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var strs sharedStrings
+	if file, ok := files[sharedStringsPath]; ok {
+		if err := decodeXML(file, &strs); err != nil {
+			return nil, err
+		}
+	}
+
+	shared := make([]string, len(strs.Items))
+	for i, item := range strs.Items {
+		shared[i] = item.Text
+		for _, run := range item.Runs {
+			shared[i] += run.Text
+		}
+	}
+
+	sheetPath, err := firstSheet(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var sheet worksheet
+	if err := decodeXML(files[sheetPath], &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, len(sheet.Rows))
+	for i, row := range sheet.Rows {
+		for j, cell := range row.Cells {
+			column := j
+			if cell.Ref != "" {
+				column = columnIndex(cell.Ref)
+			}
+
+			for len(rows[i]) <= column {
+				rows[i] = append(rows[i], "")
+			}
+
+			switch cell.Type {
+			case "s":
+				if index, err := strconv.Atoi(cell.Value); err == nil && index < len(shared) {
+					rows[i][column] = shared[index]
+				}
+			case "inlineStr":
+				rows[i][column] = cell.Inline.Text
+			default:
+				rows[i][column] = cell.Value
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+func firstSheet(files map[string]*zip.File) (string, error) {
+	var book workbook
+	var rels relationships
+
+	if file, ok := files[workbookPath]; ok {
+		if err := decodeXML(file, &book); err != nil {
+			return "", err
+		}
+	}
+
+	if file, ok := files[workbookRelsPath]; ok {
+		if err := decodeXML(file, &rels); err != nil {
+			return "", err
+		}
+	}
+
+	if len(book.Sheets) > 0 {
+		for _, rel := range rels.Items {
+			if rel.ID != book.Sheets[0].ID {
+				continue
+			}
+
+			target := strings.TrimPrefix(rel.Target, "/")
+			if !strings.HasPrefix(target, "xl/") {
+				target = path.Join("xl", target)
+			}
+
+			if _, ok := files[target]; ok {
+				return target, nil
+			}
+		}
+	}
+
+	if _, ok := files["xl/worksheets/sheet1.xml"]; ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+
+	return "", errors.New("spreadsheet has no worksheet")
+}
+
+func columnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+
+		index = index*26 + int(r-'A'+1)
+	}
+
+	return index - 1
+}
+
+func decodeXML(file *zip.File, v any) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	return xml.Unmarshal(content, v)
+}
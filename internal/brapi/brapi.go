@@ -0,0 +1,186 @@
+package brapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"stocks/currency"
+	"stocks/internal/httpjson"
+	"stocks/stock"
+	"strings"
+	"time"
+)
+
+const (
+	baseUrl = "https://brapi.dev/api"
+)
+
+type (
+	Quote struct {
+		Symbol                     string       `json:"symbol"`
+		ShortName                  string       `json:"shortName"`
+		LongName                   string       `json:"longName"`
+		RegularMarketPrice         float64      `json:"regularMarketPrice"`
+		RegularMarketOpen          float64      `json:"regularMarketOpen"`
+		RegularMarketDayHigh       float64      `json:"regularMarketDayHigh"`
+		RegularMarketDayLow        float64      `json:"regularMarketDayLow"`
+		RegularMarketChangePercent float64      `json:"regularMarketChangePercent"`
+		HistoricalDataPrice        []Historical `json:"historicalDataPrice"`
+	}
+
+	Historical struct {
+		Date   int64   `json:"date"`
+		Open   float64 `json:"open"`
+		High   float64 `json:"high"`
+		Low    float64 `json:"low"`
+		Close  float64 `json:"close"`
+		Volume int64   `json:"volume"`
+	}
+
+	Response struct {
+		Results []Quote `json:"results"`
+	}
+
+	Provider struct {
+		Client httpjson.Client
+		Token  string
+	}
+)
+
+func NewProvider(client httpjson.Client, token string) *Provider {
+	return &Provider{
+		Client: client,
+		Token:  token,
+	}
+}
+
+func (p Provider) LastInfo(ctx context.Context, symbol stock.Symbol) (stock.Info, error) {
+	quote, err := p.quote(ctx, symbol, url.Values{})
+	if err != nil {
+		return stock.Info{}, err
+	}
+
+	return quote.toStock(symbol), nil
+}
+
+func (p Provider) LastInfos(ctx context.Context, symbols []stock.Symbol) (map[stock.Symbol]stock.Info, error) {
+	names := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		names[i] = string(symbol)
+	}
+
+	response, err := p.quotes(ctx, strings.Join(names, ","), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make(map[stock.Symbol]stock.Info, len(response.Results))
+	for _, quote := range response.Results {
+		if quote.Symbol != "" {
+			infos[stock.Symbol(quote.Symbol)] = quote.toStock(stock.Symbol(quote.Symbol))
+		}
+	}
+
+	return infos, nil
+}
+
+func (p Provider) Details(ctx context.Context, symbol stock.Symbol) (stock.Details, error) {
+	quote, err := p.quote(ctx, symbol, url.Values{})
+	if err != nil {
+		return stock.Details{}, err
+	}
+
+	return quote.toStock(symbol).Details, nil
+}
+
+func (p Provider) History(ctx context.Context, symbol stock.Symbol, from, to time.Time) (stock.Candles, error) {
+	quote, err := p.quote(ctx, symbol, url.Values{"range": {historyRange(from)}, "interval": {"1d"}})
+	if err != nil {
+		return nil, err
+	}
+
+	var candles stock.Candles
+	for _, historical := range quote.HistoricalDataPrice {
+		date := time.Unix(historical.Date, 0).UTC()
+
+		candles = append(candles, stock.Candle{
+			Symbol: symbol,
+			Date:   time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+			Open:   currency.NewFromFloat(historical.Open),
+			High:   currency.NewFromFloat(historical.High),
+			Low:    currency.NewFromFloat(historical.Low),
+			Close:  currency.NewFromFloat(historical.Close),
+			Volume: historical.Volume,
+		})
+	}
+
+	return candles.Between(from, to), nil
+}
+
+func (p Provider) quote(ctx context.Context, symbol stock.Symbol, query url.Values) (Quote, error) {
+	response, err := p.quotes(ctx, string(symbol), query)
+	if err != nil {
+		return Quote{}, err
+	} else if len(response.Results) == 0 || response.Results[0].Symbol == "" {
+		return Quote{}, fmt.Errorf("%s not found", symbol)
+	}
+
+	return response.Results[0], nil
+}
+
+func (p Provider) quotes(ctx context.Context, symbols string, query url.Values) (Response, error) {
+	if p.Token != "" {
+		query.Set("token", p.Token)
+	}
+
+	address := fmt.Sprintf("%s/quote/%s", baseUrl, url.PathEscape(symbols))
+	if len(query) > 0 {
+		address = fmt.Sprintf("%s?%s", address, query.Encode())
+	}
+
+	return httpjson.Get[Response](ctx, p.Client, address)
+}
+
+func (q Quote) toStock(symbol stock.Symbol) stock.Info {
+	name := q.LongName
+	if name == "" {
+		name = q.ShortName
+	}
+
+	return stock.Info{
+		Symbol:       symbol,
+		OpeningPrice: currency.NewFromFloat(q.RegularMarketOpen),
+		MaxPrice:     currency.NewFromFloat(q.RegularMarketDayHigh),
+		MinPrice:     currency.NewFromFloat(q.RegularMarketDayLow),
+		LastPrice:    currency.NewFromFloat(q.RegularMarketPrice),
+		Change:       q.RegularMarketChangePercent,
+		Details: stock.Details{
+			Symbol: symbol,
+			Class:  stock.Unclassified,
+			Name:   name,
+		},
+	}
+}
+
+func historyRange(from time.Time) string {
+	switch days := time.Since(from).Hours() / 24; {
+	case days <= 5:
+		return "5d"
+	case days <= 30:
+		return "1mo"
+	case days <= 90:
+		return "3mo"
+	case days <= 180:
+		return "6mo"
+	case days <= 365:
+		return "1y"
+	case days <= 2*365:
+		return "2y"
+	case days <= 5*365:
+		return "5y"
+	case days <= 10*365:
+		return "10y"
+	default:
+		return "max"
+	}
+}
@@ -0,0 +1,95 @@
+package brapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"stocks/currency"
+	"stocks/stock"
+	"strings"
+	"testing"
+)
+
+type fakeClient map[string]string
+
+func (c fakeClient) Do(r *http.Request) (*http.Response, error) {
+	body, ok := c[r.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestProvider_LastInfo(t *testing.T) {
+	client := fakeClient{
+		baseUrl + "/quote/STOCK1": `{"results":[{"symbol":"STOCK1","shortName":"STOCK ONE ON","regularMarketPrice":12.5,
+			"regularMarketOpen":12,"regularMarketDayHigh":13,"regularMarketDayLow":11.5,"regularMarketChangePercent":2.5}]}`,
+		baseUrl + "/quote/STOCK2?token=secret": `{"results":[{"symbol":"STOCK2","longName":"Stock Two S.A.","regularMarketPrice":20}]}`,
+		baseUrl + "/quote/NONE3":               `{"results":[]}`,
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		symbol  stock.Symbol
+		want    stock.Info
+		wantErr bool
+	}{
+		{
+			name:   "Should decode quote fields",
+			symbol: "STOCK1",
+			want: stock.Info{Symbol: "STOCK1", OpeningPrice: currency.NewFromFloat(12), MaxPrice: currency.NewFromFloat(13),
+				MinPrice: currency.NewFromFloat(11.5), LastPrice: currency.NewFromFloat(12.5), Change: 2.5,
+				Details: stock.Details{Symbol: "STOCK1", Class: stock.Unclassified, Name: "STOCK ONE ON"}},
+		},
+		{
+			name:   "Should send the token and prefer the long name",
+			token:  "secret",
+			symbol: "STOCK2",
+			want: stock.Info{Symbol: "STOCK2", LastPrice: currency.NewFromFloat(20),
+				Details: stock.Details{Symbol: "STOCK2", Class: stock.Unclassified, Name: "Stock Two S.A."}},
+		},
+		{
+			name:    "Should return error if symbol is not found",
+			symbol:  "NONE3",
+			wantErr: true,
+		},
+		{
+			name:    "Should return error for unsuccessful responses",
+			symbol:  "FAIL3",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewProvider(client, tt.token).LastInfo(context.Background(), tt.symbol)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LastInfo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LastInfo() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvider_LastInfos(t *testing.T) {
+	client := fakeClient{
+		baseUrl + "/quote/STOCK1%2CNONE3": `{"results":[{"symbol":"STOCK1","regularMarketPrice":12.5},{"symbol":""}]}`,
+	}
+
+	got, err := NewProvider(client, "").LastInfos(context.Background(), []stock.Symbol{"STOCK1", "NONE3"})
+	if err != nil {
+		t.Fatalf("LastInfos() error = %v", err)
+	}
+
+	want := map[stock.Symbol]stock.Info{
+		"STOCK1": {Symbol: "STOCK1", LastPrice: currency.NewFromFloat(12.5), Details: stock.Details{Symbol: "STOCK1", Class: stock.Unclassified}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LastInfos() got = %v, want %v", got, want)
+	}
+}
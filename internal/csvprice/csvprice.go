@@ -0,0 +1,138 @@
+package csvprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"stocks/csv"
+	"stocks/currency"
+	"stocks/stock"
+	"strconv"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+)
+
+type (
+	Provider struct {
+		Path string
+	}
+)
+
+func NewProvider(path string) *Provider {
+	return &Provider{
+		Path: path,
+	}
+}
+
+func (p Provider) LastInfo(ctx context.Context, symbol stock.Symbol) (stock.Info, error) {
+	infos, err := p.LastInfos(ctx, []stock.Symbol{symbol})
+	if err != nil {
+		return stock.Info{}, err
+	}
+
+	info, ok := infos[symbol]
+	if !ok {
+		return stock.Info{}, fmt.Errorf("%s not found in %s", symbol, p.Path)
+	}
+
+	return info, nil
+}
+
+func (p Provider) LastInfos(_ context.Context, symbols []stock.Symbol) (map[stock.Symbol]stock.Info, error) {
+	candles, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make(map[stock.Symbol]stock.Info, len(symbols))
+	for _, symbol := range symbols {
+		last, ok := candles.Of(symbol).At(time.Now())
+		if !ok {
+			continue
+		}
+
+		infos[symbol] = stock.Info{
+			Symbol:       symbol,
+			OpeningPrice: last.Open,
+			MaxPrice:     last.High,
+			MinPrice:     last.Low,
+			LastPrice:    last.Close,
+		}
+	}
+
+	return infos, nil
+}
+
+func (p Provider) Details(_ context.Context, symbol stock.Symbol) (stock.Details, error) {
+	return stock.Details{}, fmt.Errorf("details of %s are not available in %s", symbol, p.Path)
+}
+
+func (p Provider) History(_ context.Context, symbol stock.Symbol, from, to time.Time) (stock.Candles, error) {
+	candles, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+
+	return candles.Of(symbol).Between(from, to), nil
+}
+
+func (p Provider) read() (stock.Candles, error) {
+	file, err := os.Open(p.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return csv.Import(file, true, ParseFromCSV)
+}
+
+func ParseFromCSV(elements []string) (stock.Candle, error) {
+	if len(elements) < 3 {
+		return stock.Candle{}, errors.New("invalid length")
+	}
+
+	date, err := time.Parse(dateLayout, elements[1])
+	if err != nil {
+		return stock.Candle{}, err
+	}
+
+	closing, err := currency.Parse(elements[2])
+	if err != nil {
+		return stock.Candle{}, err
+	}
+
+	candle := stock.Candle{
+		Symbol: stock.Symbol(elements[0]),
+		Date:   date,
+		Open:   closing,
+		High:   closing,
+		Low:    closing,
+		Close:  closing,
+	}
+
+	prices := []*currency.Currency{&candle.Open, &candle.High, &candle.Low}
+	for i, price := range prices {
+		if len(elements) <= 3+i || elements[3+i] == "" {
+			continue
+		}
+
+		if *price, err = currency.Parse(elements[3+i]); err != nil {
+			return stock.Candle{}, err
+		}
+	}
+
+	if len(elements) > 6 && elements[6] != "" {
+		if candle.Volume, err = strconv.ParseInt(elements[6], 10, 64); err != nil {
+			return stock.Candle{}, err
+		}
+	}
+
+	return candle, nil
+}
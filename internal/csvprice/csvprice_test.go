@@ -0,0 +1,77 @@
+package csvprice
+
+import (
+	"reflect"
+	"stocks/currency"
+	"stocks/stock"
+	"testing"
+	"time"
+)
+
+func TestParseFromCSV(t *testing.T) {
+	type args struct {
+		elements []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    stock.Candle
+		wantErr bool
+	}{
+		{
+			name: "Should parse closing price only",
+			args: args{
+				elements: []string{"STOCK1", "2023-12-29", "12.34"},
+			},
+			want: stock.Candle{
+				Symbol: "STOCK1",
+				Date:   time.Date(2023, 12, 29, 0, 0, 0, 0, time.UTC),
+				Open:   currency.New(1234),
+				High:   currency.New(1234),
+				Low:    currency.New(1234),
+				Close:  currency.New(1234),
+			},
+		},
+		{
+			name: "Should parse full candle",
+			args: args{
+				elements: []string{"STOCK1", "2023-12-29", "12.34", "12.00", "12.50", "11.90", "1000"},
+			},
+			want: stock.Candle{
+				Symbol: "STOCK1",
+				Date:   time.Date(2023, 12, 29, 0, 0, 0, 0, time.UTC),
+				Open:   currency.New(1200),
+				High:   currency.New(1250),
+				Low:    currency.New(1190),
+				Close:  currency.New(1234),
+				Volume: 1000,
+			},
+		},
+		{
+			name: "Should return error if price is invalid",
+			args: args{
+				elements: []string{"STOCK1", "2023-12-29", "abc"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if date is invalid",
+			args: args{
+				elements: []string{"STOCK1", "29/12/2023", "12.34"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFromCSV(tt.args.elements)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFromCSV() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFromCSV() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
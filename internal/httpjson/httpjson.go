@@ -0,0 +1,60 @@
+package httpjson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type (
+	Client interface {
+		Do(r *http.Request) (*http.Response, error)
+	}
+
+	StatusError struct {
+		StatusCode int
+	}
+)
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("invalid status code: %d", e.StatusCode)
+}
+
+func (e StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+func Get[T any](ctx context.Context, client Client, url string) (T, error) {
+	var output T
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return output, err
+	}
+
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return output, err
+	}
+
+	return Decode[T](res)
+}
+
+func Decode[T any](response *http.Response) (T, error) {
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	var output T
+
+	if response.StatusCode != http.StatusOK {
+		return output, StatusError{StatusCode: response.StatusCode}
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&output); err != nil {
+		return output, err
+	}
+
+	return output, nil
+}
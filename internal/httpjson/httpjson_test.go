@@ -0,0 +1,102 @@
+package httpjson
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeClient struct {
+	status int
+	body   string
+}
+
+func (c fakeClient) Do(*http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: c.status, Body: io.NopCloser(strings.NewReader(c.body))}, nil
+}
+
+func TestGet(t *testing.T) {
+	type payload struct {
+		Symbol string  `json:"symbol"`
+		Price  float64 `json:"price"`
+	}
+
+	tests := []struct {
+		name    string
+		client  fakeClient
+		want    payload
+		wantErr error
+	}{
+		{
+			name:   "Should decode successful responses",
+			client: fakeClient{status: http.StatusOK, body: `{"symbol":"STOCK1","price":12.34}`},
+			want:   payload{Symbol: "STOCK1", Price: 12.34},
+		},
+		{
+			name:    "Should return status error for unsuccessful responses",
+			client:  fakeClient{status: http.StatusTooManyRequests, body: `{"symbol":"STOCK1"}`},
+			wantErr: StatusError{StatusCode: http.StatusTooManyRequests},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Get[payload](context.Background(), tt.client, "https://example.com/quote")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGet_InvalidBody(t *testing.T) {
+	if _, err := Get[map[string]any](context.Background(), fakeClient{status: http.StatusOK, body: "<html>"}, "https://example.com"); err == nil {
+		t.Errorf("Get() expected error for invalid body")
+	}
+}
+
+func TestStatusError_Temporary(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   bool
+	}{
+		{
+			name:   "Should retry rate limited requests",
+			status: http.StatusTooManyRequests,
+			want:   true,
+		},
+		{
+			name:   "Should retry server errors",
+			status: http.StatusInternalServerError,
+			want:   true,
+		},
+		{
+			name:   "Should retry unavailable servers",
+			status: http.StatusServiceUnavailable,
+			want:   true,
+		},
+		{
+			name:   "Should not retry missing resources",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "Should not retry bad requests",
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (StatusError{StatusCode: tt.status}).Temporary(); got != tt.want {
+				t.Errorf("Temporary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,37 +2,71 @@ package mfinance
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"stocks/currency"
+	"stocks/internal/httpjson"
 	"stocks/stock"
+	"strings"
+	"time"
 )
 
 const (
-	baseUrl = "https://mfinance.com.br/api/v1"
+	baseUrl    = "https://mfinance.com.br/api/v1"
+	dateLayout = "2006-01-02"
+	monthHours = 24 * 30
+	fundSuffix = "11"
 )
 
+var paths = map[stock.Class]string{
+	stock.Stock:          "stocks",
+	stock.RealEstateFund: "fiis",
+}
+
 type (
 	Info struct {
-		Change       float64 `json:"change"`
-		ClosingPrice float64 `json:"closingPrice"`
-		Eps          float64 `json:"eps"`
-		High         float64 `json:"high"`
-		LastPrice    float64 `json:"lastPrice"`
-		LastYearHigh float64 `json:"lastYearHigh"`
-		LastYearLow  float64 `json:"lastYearLow"`
-		Low          float64 `json:"low"`
-		MarketCap    int64   `json:"marketCap"`
-		Name         string  `json:"name"`
-		Pe           float64 `json:"pe"`
-		PriceOpen    float64 `json:"priceOpen"`
-		Shares       int64   `json:"shares"`
-		Symbol       string  `json:"symbol"`
-		Volume       int     `json:"volume"`
-		VolumeAvg    int     `json:"volumeAvg"`
-		Sector       string  `json:"sector"`
-		SubSector    string  `json:"subSector"`
-		Segment      string  `json:"segment"`
+		Change        float64 `json:"change"`
+		ClosingPrice  float64 `json:"closingPrice"`
+		Eps           float64 `json:"eps"`
+		High          float64 `json:"high"`
+		LastPrice     float64 `json:"lastPrice"`
+		LastYearHigh  float64 `json:"lastYearHigh"`
+		LastYearLow   float64 `json:"lastYearLow"`
+		Low           float64 `json:"low"`
+		MarketCap     int64   `json:"marketCap"`
+		Name          string  `json:"name"`
+		Pe            float64 `json:"pe"`
+		PriceOpen     float64 `json:"priceOpen"`
+		Shares        int64   `json:"shares"`
+		Symbol        string  `json:"symbol"`
+		Volume        int     `json:"volume"`
+		VolumeAvg     int     `json:"volumeAvg"`
+		Sector        string  `json:"sector"`
+		SubSector     string  `json:"subSector"`
+		Segment       string  `json:"segment"`
+		LastDividend  float64 `json:"lastDividend"`
+		DividendYield float64 `json:"dividendYield"`
+	}
+
+	List struct {
+		Stocks []Info `json:"stocks"`
+		Fiis   []Info `json:"fiis"`
+	}
+
+	Historical struct {
+		Close  float64 `json:"close"`
+		Date   string  `json:"date"`
+		High   float64 `json:"high"`
+		Low    float64 `json:"low"`
+		Open   float64 `json:"open"`
+		Volume int64   `json:"volume"`
+	}
+
+	Historicals struct {
+		Historicals []Historical `json:"historicals"`
+		Symbol      string       `json:"symbol"`
 	}
 
 	Client interface {
@@ -51,74 +85,141 @@ func NewProvider(client Client) *Provider {
 }
 
 func (p Provider) LastInfo(ctx context.Context, symbol stock.Symbol) (stock.Info, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/stocks/%s", baseUrl, symbol), nil)
+	info, class, err := p.find(ctx, symbol)
 	if err != nil {
 		return stock.Info{}, err
 	}
 
-	req = req.WithContext(ctx)
-	res, err := p.Client.Do(req)
-	if err != nil {
-		return stock.Info{}, err
-	}
+	return info.toStock(symbol, class), nil
+}
 
-	info, err := decode[Info](res)
-	if err != nil {
-		return stock.Info{}, err
+func (p Provider) LastInfos(ctx context.Context, symbols []stock.Symbol) (map[stock.Symbol]stock.Info, error) {
+	infos := make(map[stock.Symbol]stock.Info, len(symbols))
+
+	for _, class := range []stock.Class{stock.Stock, stock.RealEstateFund} {
+		var names []string
+		for _, symbol := range symbols {
+			if _, ok := infos[symbol]; !ok {
+				names = append(names, string(symbol))
+			}
+		}
+
+		if len(names) == 0 {
+			break
+		}
+
+		address := fmt.Sprintf("%s/%s?symbols=%s", baseUrl, paths[class], url.QueryEscape(strings.Join(names, ",")))
+		list, err := httpjson.Get[List](ctx, p.Client, address)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, info := range append(list.Stocks, list.Fiis...) {
+			if info.Symbol != "" {
+				infos[stock.Symbol(info.Symbol)] = info.toStock(stock.Symbol(info.Symbol), class)
+			}
+		}
 	}
 
-	return stock.Info{
-		Symbol:       symbol,
-		OpeningPrice: info.PriceOpen,
-		MaxPrice:     info.High,
-		MinPrice:     info.Low,
-		LastPrice:    info.LastPrice,
-		Change:       info.Change,
-	}, nil
+	return infos, nil
 }
 
 func (p Provider) Details(ctx context.Context, symbol stock.Symbol) (stock.Details, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/stocks/%s", baseUrl, symbol), nil)
+	info, class, err := p.find(ctx, symbol)
 	if err != nil {
 		return stock.Details{}, err
 	}
 
-	req = req.WithContext(ctx)
-	res, err := p.Client.Do(req)
-	if err != nil {
-		return stock.Details{}, err
+	return info.toStock(symbol, class).Details, nil
+}
+
+func (p Provider) History(ctx context.Context, symbol stock.Symbol, from, to time.Time) (stock.Candles, error) {
+	months := int(time.Since(from).Hours()/monthHours) + 1
+
+	var historicals Historicals
+	for _, class := range classes(symbol) {
+		address := fmt.Sprintf("%s/%s/historicals/%s?months=%d", baseUrl, paths[class], symbol, months)
+
+		var err error
+		if historicals, err = httpjson.Get[Historicals](ctx, p.Client, address); notFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		} else if len(historicals.Historicals) > 0 {
+			break
+		}
 	}
 
-	details, err := decode[Info](res)
-	if err != nil {
-		return stock.Details{}, err
-	} else if details.Symbol == "" {
-		return stock.Details{}, fmt.Errorf("%s not found", symbol)
+	var candles stock.Candles
+	for _, historical := range historicals.Historicals {
+		if len(historical.Date) < len(dateLayout) {
+			return nil, fmt.Errorf("invalid date: %s", historical.Date)
+		}
+
+		date, err := time.Parse(dateLayout, historical.Date[:len(dateLayout)])
+		if err != nil {
+			return nil, err
+		}
+
+		candles = append(candles, stock.Candle{
+			Symbol: symbol,
+			Date:   date,
+			Open:   currency.NewFromFloat(historical.Open),
+			High:   currency.NewFromFloat(historical.High),
+			Low:    currency.NewFromFloat(historical.Low),
+			Close:  currency.NewFromFloat(historical.Close),
+			Volume: historical.Volume,
+		})
 	}
 
-	return stock.Details{
-		Symbol:    symbol,
-		Name:      details.Name,
-		Sector:    details.Sector,
-		SubSector: details.SubSector,
-		Segment:   details.Segment,
-	}, nil
+	return candles.Between(from, to), nil
 }
 
-func decode[T any](response *http.Response) (T, error) {
-	defer func() {
-		_ = response.Body.Close()
-	}()
+func (p Provider) find(ctx context.Context, symbol stock.Symbol) (Info, stock.Class, error) {
+	for _, class := range classes(symbol) {
+		info, err := httpjson.Get[Info](ctx, p.Client, fmt.Sprintf("%s/%s/%s", baseUrl, paths[class], symbol))
+		if notFound(err) {
+			continue
+		} else if err != nil {
+			return Info{}, 0, err
+		} else if info.Symbol != "" {
+			return info, class, nil
+		}
+	}
 
-	var output T
+	return Info{}, 0, fmt.Errorf("%s not found", symbol)
+}
 
-	if response.StatusCode != http.StatusOK {
-		return output, fmt.Errorf("invalid status code: %d", response.StatusCode)
+func (i Info) toStock(symbol stock.Symbol, class stock.Class) stock.Info {
+	return stock.Info{
+		Symbol:       symbol,
+		OpeningPrice: currency.NewFromFloat(i.PriceOpen),
+		MaxPrice:     currency.NewFromFloat(i.High),
+		MinPrice:     currency.NewFromFloat(i.Low),
+		LastPrice:    currency.NewFromFloat(i.LastPrice),
+		Change:       i.Change,
+		Details: stock.Details{
+			Symbol:        symbol,
+			Class:         class,
+			Name:          i.Name,
+			Sector:        i.Sector,
+			SubSector:     i.SubSector,
+			Segment:       i.Segment,
+			LastDividend:  currency.NewFromFloat(i.LastDividend),
+			DividendYield: i.DividendYield,
+		},
 	}
+}
 
-	if err := json.NewDecoder(response.Body).Decode(&output); err != nil {
-		return output, err
+func classes(symbol stock.Symbol) []stock.Class {
+	if strings.HasSuffix(string(symbol), fundSuffix) {
+		return []stock.Class{stock.RealEstateFund, stock.Stock}
 	}
 
-	return output, nil
+	return []stock.Class{stock.Stock, stock.RealEstateFund}
+}
+
+func notFound(err error) bool {
+	var status httpjson.StatusError
+	return errors.As(err, &status) && status.StatusCode == http.StatusNotFound
 }
@@ -0,0 +1,73 @@
+package mfinance
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"stocks/currency"
+	"stocks/stock"
+	"strings"
+	"testing"
+)
+
+type fakeClient map[string]string
+
+func (c fakeClient) Do(r *http.Request) (*http.Response, error) {
+	body, ok := c[r.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestProvider_Details(t *testing.T) {
+	client := fakeClient{
+		baseUrl + "/stocks/STOCK3": `{"symbol":"STOCK3","name":"Stock 3","sector":"Energy"}`,
+		baseUrl + "/fiis/HGLG11":   `{"symbol":"HGLG11","name":"Fund","segment":"Logistics","lastDividend":1.1,"dividendYield":0.75}`,
+		baseUrl + "/fiis/BOVA11":   `{}`,
+		baseUrl + "/stocks/BOVA11": `{"symbol":"BOVA11","name":"ETF"}`,
+	}
+
+	tests := []struct {
+		name    string
+		symbol  stock.Symbol
+		want    stock.Details
+		wantErr bool
+	}{
+		{
+			name:   "Should fetch stock details",
+			symbol: "STOCK3",
+			want:   stock.Details{Symbol: "STOCK3", Class: stock.Stock, Name: "Stock 3", Sector: "Energy"},
+		},
+		{
+			name:   "Should fetch real estate fund details",
+			symbol: "HGLG11",
+			want: stock.Details{Symbol: "HGLG11", Class: stock.RealEstateFund, Name: "Fund", Segment: "Logistics",
+				LastDividend: currency.New(110), DividendYield: 0.75},
+		},
+		{
+			name:   "Should fall back to stocks endpoint for units",
+			symbol: "BOVA11",
+			want:   stock.Details{Symbol: "BOVA11", Class: stock.Stock, Name: "ETF"},
+		},
+		{
+			name:    "Should return error if symbol is not found",
+			symbol:  "NONE3",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewProvider(client).Details(context.Background(), tt.symbol)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Details() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Details() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
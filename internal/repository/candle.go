@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"stocks/currency"
+	"stocks/stock"
+	"time"
+)
+
+type (
+	Candle struct {
+		gorm.Model
+		Symbol string    `gorm:"uniqueIndex:idx_candles_symbol_date"`
+		Date   time.Time `gorm:"uniqueIndex:idx_candles_symbol_date"`
+		Open   int64     `gorm:"column:open_cents"`
+		High   int64     `gorm:"column:high_cents"`
+		Low    int64     `gorm:"column:low_cents"`
+		Close  int64     `gorm:"column:close_cents"`
+		Volume int64
+	}
+)
+
+func (d GormDatabase) InsertCandles(ctx context.Context, candles stock.Candles) error {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	entities := make([]Candle, len(candles))
+	for i, c := range candles {
+		entities[i] = Candle{
+			Symbol: string(c.Symbol),
+			Date:   c.Date,
+			Open:   c.Open.Cents(),
+			High:   c.High.Cents(),
+			Low:    c.Low.Cents(),
+			Close:  c.Close.Cents(),
+			Volume: c.Volume,
+		}
+	}
+
+	return d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "open_cents", "high_cents", "low_cents", "close_cents", "volume"}),
+	}).Create(&entities).Error
+}
+
+func (d GormDatabase) Candles(ctx context.Context, symbol stock.Symbol, from, to time.Time) (stock.Candles, error) {
+	var entities []Candle
+	query := d.DB.WithContext(ctx).Where("symbol = ? AND date >= ? AND date <= ?", symbol, from, to).Order("date").Find(&entities)
+	if query.Error != nil {
+		return nil, query.Error
+	}
+
+	candles := make(stock.Candles, len(entities))
+	for i, e := range entities {
+		candles[i] = stock.Candle{
+			Symbol: stock.Symbol(e.Symbol),
+			Date:   e.Date,
+			Open:   currency.New(e.Open),
+			High:   currency.New(e.High),
+			Low:    currency.New(e.Low),
+			Close:  currency.New(e.Close),
+			Volume: e.Volume,
+		}
+	}
+
+	return candles, nil
+}
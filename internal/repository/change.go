@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"stocks/operation"
+)
+
+type (
+	OperationChange struct {
+		gorm.Model
+		OperationID     uint `gorm:"index"`
+		Action          string
+		OperationValues `gorm:"embedded"`
+	}
+)
+
+func (d GormDatabase) Changes(ctx context.Context) (operation.Changes, error) {
+	var entities []OperationChange
+	if query := d.DB.WithContext(ctx).Order("id").Find(&entities); query.Error != nil {
+		return nil, query.Error
+	}
+
+	changes := make(operation.Changes, len(entities))
+	for i, e := range entities {
+		previous := e.OperationValues.toOperation()
+		previous.ID = e.OperationID
+
+		changes[i] = operation.Change{
+			Action:   operation.Action(e.Action),
+			Previous: previous,
+			Date:     e.CreatedAt,
+		}
+	}
+
+	return changes, nil
+}
+
+func newOperationChange(entity Operation, action operation.Action) *OperationChange {
+	return &OperationChange{
+		OperationID:     entity.ID,
+		Action:          string(action),
+		OperationValues: entity.OperationValues,
+	}
+}
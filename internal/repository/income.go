@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"stocks/currency"
+	"stocks/income"
+	"stocks/stock"
+	"time"
+)
+
+type (
+	Income struct {
+		gorm.Model
+		Symbol      string
+		Type        int
+		Amount      int64 `gorm:"column:amount_cents;default:0"`
+		WithheldTax int64 `gorm:"column:withheld_tax_cents;default:0"`
+		Date        time.Time
+		Portfolio   string `gorm:"index"`
+	}
+)
+
+func (d GormDatabase) InsertIncome(ctx context.Context, i income.Income) error {
+	if err := d.checkPortfolio(ctx, i.Portfolio); err != nil {
+		return err
+	}
+
+	return d.DB.WithContext(ctx).Create(&Income{
+		Symbol:      string(i.Symbol),
+		Type:        int(i.Type),
+		Amount:      i.Amount.Cents(),
+		WithheldTax: i.WithheldTax.Cents(),
+		Date:        i.Date,
+		Portfolio:   i.Portfolio,
+	}).Error
+}
+
+func (d GormDatabase) InsertIncomes(ctx context.Context, incomes income.List) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, i := range incomes {
+			if err := (GormDatabase{DB: tx}).InsertIncome(ctx, i); err != nil {
+				return fmt.Errorf("%s %s %s: %w", i.Symbol, i.Type, i.Date.Format("2006-01-02"), err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (d GormDatabase) Incomes(ctx context.Context) (income.List, error) {
+	var entities []Income
+	if query := d.DB.WithContext(ctx).Order("date, id").Find(&entities); query.Error != nil {
+		return nil, query.Error
+	}
+
+	incomes := make(income.List, len(entities))
+	for i, e := range entities {
+		incomes[i] = income.Income{
+			Symbol:      stock.Symbol(e.Symbol),
+			Type:        income.Type(e.Type),
+			Amount:      currency.New(e.Amount),
+			WithheldTax: currency.New(e.WithheldTax),
+			Date:        e.Date,
+			Portfolio:   e.Portfolio,
+		}
+	}
+
+	return incomes, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"stocks/operation"
+	"stocks/portfolio"
+)
+
+type legacyMoney struct {
+	table   string
+	columns []string
+}
+
+var legacyMoneyColumns = []legacyMoney{
+	{table: "operations", columns: []string{"unit_value", "brokerage", "emoluments", "settlement", "iss", "withheld_tax"}},
+	{table: "incomes", columns: []string{"amount", "withheld_tax"}},
+}
+
+func migrateCents(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, legacy := range legacyMoneyColumns {
+			for _, column := range legacy.columns {
+				if !tx.Migrator().HasColumn(legacy.table, column) {
+					continue
+				}
+
+				update := fmt.Sprintf("UPDATE %s SET %s_cents = CAST(ROUND(%s * 100) AS INTEGER) WHERE %s IS NOT NULL",
+					legacy.table, column, column, column)
+				if err := tx.Exec(update).Error; err != nil {
+					return err
+				}
+
+				if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", legacy.table, column)).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func migratePortfolios(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(Portfolio{Name: portfolio.Default}).FirstOrCreate(&Portfolio{}).Error; err != nil {
+			return err
+		}
+
+		trades := []int{int(operation.Buy), int(operation.Sell)}
+		if err := tx.Exec("UPDATE operations SET portfolio = ? WHERE portfolio IS NULL AND type IN ?",
+			portfolio.Default, trades).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("UPDATE operations SET portfolio = '' WHERE portfolio IS NULL").Error; err != nil {
+			return err
+		}
+
+		return tx.Exec("UPDATE incomes SET portfolio = ? WHERE portfolio IS NULL", portfolio.Default).Error
+	})
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"gorm.io/gorm"
 	"stocks/asset"
 	"stocks/currency"
+	"stocks/income"
 	"stocks/operation"
 	"stocks/stock"
 	"time"
@@ -18,121 +20,265 @@ type (
 
 	Operation struct {
 		gorm.Model
-		Symbol    string
-		Type      int
-		Quantity  int
-		UnitValue float64
-		Date      time.Time
+		OperationValues `gorm:"embedded"`
 	}
 
-	Detail struct {
-		gorm.Model
-		Symbol    string
-		Name      string
-		Sector    string
-		SubSector string
-		Segment   string
+	OperationValues struct {
+		Symbol      string
+		Type        int
+		Quantity    int
+		UnitValue   int64   `gorm:"column:unit_value_cents;default:0"`
+		Brokerage   int64   `gorm:"column:brokerage_cents;default:0"`
+		Emoluments  int64   `gorm:"column:emoluments_cents;default:0"`
+		Settlement  int64   `gorm:"column:settlement_cents;default:0"`
+		ISS         int64   `gorm:"column:iss_cents;default:0"`
+		WithheldTax int64   `gorm:"column:withheld_tax_cents;default:0"`
+		Ratio       float64 `gorm:"default:0"`
+		Date        time.Time
+		Portfolio   string `gorm:"index"`
+		Note        string
+		Lot         time.Time
+		Target      string
 	}
 
-	Asset struct {
-		Symbol       stock.Symbol
-		Quantity     int
-		AveragePrice float64
-		CurrentPrice float64
-		Investment   float64
-		Settled      float64
+	Detail struct {
+		gorm.Model
+		Symbol        string
+		Class         int `gorm:"default:0"`
+		Name          string
+		CNPJ          string `gorm:"column:cnpj"`
+		Sector        string
+		SubSector     string
+		Segment       string
+		LastDividend  int64   `gorm:"column:last_dividend_cents;default:0"`
+		DividendYield float64 `gorm:"default:0"`
 	}
-
-	Assets []Asset
 )
 
-func (s Assets) ToDomain() asset.Assets {
-	var a asset.Assets
-
-	for _, e := range s {
-		a = append(a, asset.Asset{
-			Symbol:       e.Symbol,
-			Quantity:     e.Quantity,
-			AveragePrice: currency.NewFromFloat(e.AveragePrice),
-			LastPrice:    currency.NewFromFloat(e.CurrentPrice),
-			Investment:   currency.NewFromFloat(e.Investment),
-			Settled:      currency.NewFromFloat(e.Settled),
-		})
+func NewGormDatabase(db *gorm.DB) (*GormDatabase, error) {
+	if err := db.AutoMigrate(&Operation{}, &Detail{}, &Income{}, &Candle{}, &Quote{}, &Portfolio{}, &OperationChange{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
-	return a
-}
+	if err := migrateCents(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate amounts to cents: %w", err)
+	}
 
-func NewGormDatabase(db *gorm.DB) *GormDatabase {
-	_ = db.AutoMigrate(&Operation{}, &Detail{})
+	if err := migratePortfolios(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate portfolios: %w", err)
+	}
 
 	return &GormDatabase{
 		DB: db,
-	}
+	}, nil
 }
 
 func (d GormDatabase) Create(ctx context.Context, op operation.Operation) error {
+	if err := d.checkOperation(ctx, op); err != nil {
+		return err
+	}
+
 	return d.DB.WithContext(ctx).Create(&Operation{
-		Symbol:    string(op.Symbol),
-		Type:      int(op.Type),
-		Quantity:  op.Quantity,
-		UnitValue: op.UnitValue,
-		Date:      op.Date,
+		OperationValues: newOperationValues(op),
 	}).Error
 }
 
+func (d GormDatabase) CreateAll(ctx context.Context, operations operation.List) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, op := range operations {
+			if err := (GormDatabase{DB: tx}).Create(ctx, op); err != nil {
+				return fmt.Errorf("%v: %w", op, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (d GormDatabase) CreateStatement(ctx context.Context, operations operation.List, incomes income.List) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		db := GormDatabase{DB: tx}
+		if err := db.CreateAll(ctx, operations); err != nil {
+			return err
+		}
+
+		return db.InsertIncomes(ctx, incomes)
+	})
+}
+
 func (d GormDatabase) List(ctx context.Context) (operation.List, error) {
 	var entities []Operation
-	query := d.DB.WithContext(ctx).Raw("SELECT * FROM operations ORDER BY date, id").Scan(&entities)
-	if query.Error != nil {
+	if query := d.DB.WithContext(ctx).Order("date, id").Find(&entities); query.Error != nil {
 		return nil, query.Error
 	}
 
 	operations := make(operation.List, len(entities))
 	for i, e := range entities {
-		operations[i] = operation.Operation{
-			Symbol:    stock.Symbol(e.Symbol),
-			Type:      operation.Type(e.Type),
-			Quantity:  e.Quantity,
-			UnitValue: e.UnitValue,
-			Date:      e.Date,
-		}
+		operations[i] = e.toOperation()
 	}
 
 	return operations, nil
 }
 
-func (d GormDatabase) Assets(ctx context.Context) (asset.Assets, error) {
-	var a Assets
-
-	query := d.DB.WithContext(ctx).Raw(`
-		SELECT buy.symbol                                          symbol,
-			   buy.total_quantity - IFNULL(sell.total_quantity, 0) quantity,
-			   buy.average_price                                   average_price,
-			   buy.total_amount                                    investment,
-			   sell.total_amount                                   settled
-		FROM (SELECT symbol                                               symbol,
-					 round(sum(quantity * unit_value), 2)                 total_amount,
-					 sum(quantity)                                        total_quantity,
-					 round(sum(quantity * unit_value) / sum(quantity), 2) average_price
-			  FROM operations
-			  WHERE type = ?
-			  GROUP BY symbol
-			  ORDER BY symbol) buy
-				 LEFT JOIN (SELECT symbol                               symbol,
-								   round(sum(quantity * unit_value), 2) total_amount,
-								   sum(quantity)                        total_quantity
-							FROM operations
-							WHERE type = ?
-							GROUP BY symbol) sell
-						   ON sell.symbol == buy.symbol;
-	`, operation.Buy, operation.Sell).Scan(&a)
+func (d GormDatabase) Get(ctx context.Context, id uint) (operation.Operation, error) {
+	entity, err := findOperation(d.DB.WithContext(ctx), id)
+	if err != nil {
+		return operation.Operation{}, err
+	}
 
-	if query.Error != nil {
-		return nil, query.Error
+	return entity.toOperation(), nil
+}
+
+func (d GormDatabase) Update(ctx context.Context, op operation.Operation) error {
+	if err := d.checkOperation(ctx, op); err != nil {
+		return err
 	}
 
-	return a.ToDomain(), nil
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		entity, err := findOperation(tx, op.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(newOperationChange(entity, operation.Updated)).Error; err != nil {
+			return err
+		}
+
+		entity.OperationValues = newOperationValues(op)
+		return tx.Save(&entity).Error
+	})
+}
+
+func (d GormDatabase) Delete(ctx context.Context, id uint) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		entity, err := findOperation(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(newOperationChange(entity, operation.Deleted)).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entity).Error
+	})
+}
+
+func (d GormDatabase) checkOperation(ctx context.Context, op operation.Operation) error {
+	if op.Type.IsEvent() {
+		return nil
+	}
+
+	return d.checkPortfolio(ctx, op.Portfolio)
+}
+
+func findOperation(db *gorm.DB, id uint) (Operation, error) {
+	var entity Operation
+	if query := db.Find(&entity, id); query.Error != nil {
+		return Operation{}, query.Error
+	} else if query.RowsAffected == 0 {
+		return Operation{}, fmt.Errorf("operation %d not found", id)
+	}
+
+	return entity, nil
+}
+
+func newOperationValues(op operation.Operation) OperationValues {
+	return OperationValues{
+		Symbol:      string(op.Symbol),
+		Type:        int(op.Type),
+		Quantity:    op.Quantity,
+		UnitValue:   op.UnitValue.Cents(),
+		Brokerage:   op.Fees.Brokerage.Cents(),
+		Emoluments:  op.Fees.Emoluments.Cents(),
+		Settlement:  op.Fees.Settlement.Cents(),
+		ISS:         op.Fees.ISS.Cents(),
+		WithheldTax: op.WithheldTax.Cents(),
+		Ratio:       op.Ratio,
+		Date:        op.Date,
+		Portfolio:   op.Portfolio,
+		Note:        op.Note,
+		Lot:         op.Lot,
+		Target:      string(op.Target),
+	}
+}
+
+func (e Operation) toOperation() operation.Operation {
+	op := e.OperationValues.toOperation()
+	op.ID = e.ID
+
+	return op
+}
+
+func (v OperationValues) toOperation() operation.Operation {
+	return operation.Operation{
+		Symbol:    stock.Symbol(v.Symbol),
+		Type:      operation.Type(v.Type),
+		Quantity:  v.Quantity,
+		UnitValue: currency.New(v.UnitValue),
+		Fees: operation.Fees{
+			Brokerage:  currency.New(v.Brokerage),
+			Emoluments: currency.New(v.Emoluments),
+			Settlement: currency.New(v.Settlement),
+			ISS:        currency.New(v.ISS),
+		},
+		WithheldTax: currency.New(v.WithheldTax),
+		Ratio:       v.Ratio,
+		Date:        v.Date,
+		Portfolio:   v.Portfolio,
+		Note:        v.Note,
+		Lot:         v.Lot,
+		Target:      stock.Symbol(v.Target),
+	}
+}
+
+func (d GormDatabase) Assets(ctx context.Context, portfolio string) (asset.Assets, error) {
+	operations, err := d.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	incomes, err := d.Incomes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	classes, err := d.classes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolios, err := d.Portfolios(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return assets(operations.In(portfolio).Ledger(portfolios.Methods()), incomes.In(portfolio), classes), nil
+}
+
+func (d GormDatabase) AssetsAt(ctx context.Context, portfolio string, date time.Time) (asset.Assets, error) {
+	operations, err := d.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	incomes, err := d.Incomes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	classes, err := d.classes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolios, err := d.Portfolios(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := operations.In(portfolio).Until(date).Ledger(portfolios.Methods())
+	return assets(ledger, incomes.In(portfolio).Between(time.Time{}, date.AddDate(0, 0, 1)), classes), nil
 }
 
 func (d GormDatabase) GetDetails(ctx context.Context, symbol stock.Symbol) (stock.Details, error) {
@@ -143,21 +289,85 @@ func (d GormDatabase) GetDetails(ctx context.Context, symbol stock.Symbol) (stoc
 		return stock.Details{}, errors.New("not found")
 	}
 
-	return stock.Details{
-		Symbol:    stock.Symbol(entity.Symbol),
-		Name:      entity.Name,
-		Sector:    entity.Sector,
-		SubSector: entity.SubSector,
-		Segment:   entity.Segment,
-	}, nil
+	return entity.toDetails(), nil
 }
 
 func (d GormDatabase) InsertDetails(ctx context.Context, details stock.Details) error {
 	return d.DB.WithContext(ctx).Create(&Detail{
-		Symbol:    string(details.Symbol),
-		Name:      details.Name,
-		Sector:    details.Sector,
-		SubSector: details.SubSector,
-		Segment:   details.Segment,
+		Symbol:        string(details.Symbol),
+		Class:         int(details.Class),
+		Name:          details.Name,
+		CNPJ:          details.CNPJ,
+		Sector:        details.Sector,
+		SubSector:     details.SubSector,
+		Segment:       details.Segment,
+		LastDividend:  details.LastDividend.Cents(),
+		DividendYield: details.DividendYield,
 	}).Error
 }
+
+func (d GormDatabase) UpdateDetails(ctx context.Context, details stock.Details) error {
+	query := d.DB.WithContext(ctx).Model(&Detail{}).Where("symbol = ?", details.Symbol).Updates(map[string]any{
+		"class":      int(details.Class),
+		"name":       details.Name,
+		"cnpj":       details.CNPJ,
+		"sector":     details.Sector,
+		"sub_sector": details.SubSector,
+		"segment":    details.Segment,
+	})
+	if query.Error != nil {
+		return query.Error
+	} else if query.RowsAffected == 0 {
+		return fmt.Errorf("details of %s not found", details.Symbol)
+	}
+
+	return nil
+}
+
+func (d GormDatabase) classes(ctx context.Context) (map[stock.Symbol]stock.Class, error) {
+	var entities []Detail
+	if query := d.DB.WithContext(ctx).Find(&entities); query.Error != nil {
+		return nil, query.Error
+	}
+
+	classes := make(map[stock.Symbol]stock.Class, len(entities))
+	for _, e := range entities {
+		classes[stock.Symbol(e.Symbol)] = stock.Class(e.Class)
+	}
+
+	return classes, nil
+}
+
+func (e Detail) toDetails() stock.Details {
+	return stock.Details{
+		Symbol:        stock.Symbol(e.Symbol),
+		Class:         stock.Class(e.Class),
+		Name:          e.Name,
+		CNPJ:          e.CNPJ,
+		Sector:        e.Sector,
+		SubSector:     e.SubSector,
+		Segment:       e.Segment,
+		LastDividend:  currency.New(e.LastDividend),
+		DividendYield: e.DividendYield,
+	}
+}
+
+func assets(ledger operation.Ledger, incomes income.List, classes map[stock.Symbol]stock.Class) asset.Assets {
+	var a asset.Assets
+	for _, position := range ledger.Positions() {
+		a = append(a, asset.Asset{
+			Symbol:       position.Symbol,
+			Class:        classes[position.Symbol],
+			Quantity:     position.Quantity,
+			AveragePrice: position.AveragePrice,
+			Cost:         position.Cost,
+			Investment:   position.Investment,
+			Settled:      position.Settled,
+			Realized:     position.Realized,
+			DayTrade:     position.DayTrade,
+			Income:       incomes.Of(position.Symbol).Net(),
+		})
+	}
+
+	return a
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"stocks/operation"
+	"stocks/portfolio"
+)
+
+type (
+	Portfolio struct {
+		gorm.Model
+		Name   string `gorm:"uniqueIndex"`
+		Broker string
+		Method int `gorm:"default:0"`
+	}
+)
+
+func (d GormDatabase) CreatePortfolio(ctx context.Context, p portfolio.Portfolio) error {
+	var count int64
+	if err := d.DB.WithContext(ctx).Model(&Portfolio{}).Where("name = ?", p.Name).Count(&count).Error; err != nil {
+		return err
+	} else if count > 0 {
+		return fmt.Errorf("portfolio %s already exists", p.Name)
+	}
+
+	return d.DB.WithContext(ctx).Create(&Portfolio{
+		Name:   p.Name,
+		Broker: p.Broker,
+		Method: int(p.Method),
+	}).Error
+}
+
+func (d GormDatabase) Portfolios(ctx context.Context) (portfolio.Portfolios, error) {
+	var entities []Portfolio
+	if query := d.DB.WithContext(ctx).Order("name").Find(&entities); query.Error != nil {
+		return nil, query.Error
+	}
+
+	portfolios := make(portfolio.Portfolios, len(entities))
+	for i, e := range entities {
+		portfolios[i] = portfolio.Portfolio{
+			Name:   e.Name,
+			Broker: e.Broker,
+			Method: operation.Method(e.Method),
+		}
+	}
+
+	return portfolios, nil
+}
+
+func (d GormDatabase) UpdatePortfolio(ctx context.Context, p portfolio.Portfolio) error {
+	query := d.DB.WithContext(ctx).Model(&Portfolio{}).Where("name = ?", p.Name).Updates(map[string]any{
+		"broker": p.Broker,
+		"method": int(p.Method),
+	})
+	if query.Error != nil {
+		return query.Error
+	} else if query.RowsAffected == 0 {
+		return fmt.Errorf("portfolio %s not found", p.Name)
+	}
+
+	return nil
+}
+
+func (d GormDatabase) checkPortfolio(ctx context.Context, name string) error {
+	var count int64
+	if err := d.DB.WithContext(ctx).Model(&Portfolio{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return err
+	} else if count == 0 {
+		return fmt.Errorf("portfolio %s not found", name)
+	}
+
+	return nil
+}
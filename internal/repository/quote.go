@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"stocks/currency"
+	"stocks/stock"
+	"time"
+)
+
+type (
+	Quote struct {
+		gorm.Model
+		Symbol       string `gorm:"uniqueIndex"`
+		OpeningPrice int64  `gorm:"column:opening_price_cents"`
+		MaxPrice     int64  `gorm:"column:max_price_cents"`
+		MinPrice     int64  `gorm:"column:min_price_cents"`
+		LastPrice    int64  `gorm:"column:last_price_cents"`
+		Change       float64
+		Source       string
+		FetchedAt    time.Time
+	}
+)
+
+func (d GormDatabase) GetInfo(ctx context.Context, symbol stock.Symbol) (stock.Info, time.Time, error) {
+	var entity Quote
+	if query := d.DB.WithContext(ctx).Find(&entity, "symbol = ?", symbol); query.Error != nil {
+		return stock.Info{}, time.Time{}, query.Error
+	} else if query.RowsAffected == 0 {
+		return stock.Info{}, time.Time{}, errors.New("not found")
+	}
+
+	return stock.Info{
+		Symbol:       stock.Symbol(entity.Symbol),
+		OpeningPrice: currency.New(entity.OpeningPrice),
+		MaxPrice:     currency.New(entity.MaxPrice),
+		MinPrice:     currency.New(entity.MinPrice),
+		LastPrice:    currency.New(entity.LastPrice),
+		Change:       entity.Change,
+		Source:       entity.Source,
+	}, entity.FetchedAt, nil
+}
+
+func (d GormDatabase) InsertInfo(ctx context.Context, info stock.Info, fetchedAt time.Time) error {
+	return d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "symbol"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "opening_price_cents", "max_price_cents",
+			"min_price_cents", "last_price_cents", "change", "source", "fetched_at"}),
+	}).Create(&Quote{
+		Symbol:       string(info.Symbol),
+		OpeningPrice: info.OpeningPrice.Cents(),
+		MaxPrice:     info.MaxPrice.Cents(),
+		MinPrice:     info.MinPrice.Cents(),
+		LastPrice:    info.LastPrice.Cents(),
+		Change:       info.Change,
+		Source:       info.Source,
+		FetchedAt:    fetchedAt,
+	}).Error
+}
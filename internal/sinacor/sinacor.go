@@ -0,0 +1,346 @@
+package sinacor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"stocks/csv"
+	"stocks/currency"
+	"stocks/operation"
+	"stocks/stock"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout = "02/01/2006"
+)
+
+var (
+	headerPattern = regexp.MustCompile(`(?i)Nr\.?\s*nota[^\n]*\n(?:\s*\n)*\s*(\d+)\s+(?:\d+\s+)?(\d{2}/\d{2}/\d{4})`)
+	tradePattern  = regexp.MustCompile(`^\s*\S*BOVESPA\s+([CV])\s+(.+?)\s+(\d[\d.]*)\s+(\d[\d.]*,\d+)\s+(\d[\d.]*,\d{2})\s+([CD])\s*$`)
+	amountPattern = regexp.MustCompile(`(\d[\d.]*,\d{2})\s*[CD]?\s*$`)
+	tickerPattern = regexp.MustCompile(`\b([A-Z]{4}\d{1,2})F?\b`)
+
+	accents = strings.NewReplacer("á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+		"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ç", "c", "Á", "a", "Ã", "a", "É", "e", "Í", "i", "Ó", "o",
+		"Õ", "o", "Ú", "u", "Ç", "c")
+
+	spotMarkets = []string{"VISTA", "FRACIONARIO"}
+
+	observations = map[string]bool{
+		"#": true, "D": true, "F": true, "B": true, "C": true, "A": true, "H": true, "X": true, "P": true,
+		"Y": true, "L": true, "T": true, "I": true, "R": true, "#D": true, "D#": true, "#F": true, "2": true, "8": true, "9": true,
+	}
+)
+
+type (
+	Note struct {
+		Number      string
+		Date        time.Time
+		Trades      []Trade
+		Fees        operation.Fees
+		WithheldTax currency.Currency
+	}
+
+	Trade struct {
+		Type          operation.Type
+		Specification string
+		Quantity      int
+		Price         currency.Currency
+		Value         currency.Currency
+	}
+
+	fee struct {
+		label  string
+		target func(*Note) *currency.Currency
+	}
+)
+
+var fees = []fee{
+	{label: "taxa de liquidacao", target: func(n *Note) *currency.Currency { return &n.Fees.Settlement }},
+	{label: "taxa de registro", target: func(n *Note) *currency.Currency { return &n.Fees.Settlement }},
+	{label: "emolumentos", target: func(n *Note) *currency.Currency { return &n.Fees.Emoluments }},
+	{label: "taxa de termo/opcoes", target: func(n *Note) *currency.Currency { return &n.Fees.Emoluments }},
+	{label: "taxa a.n.a.", target: func(n *Note) *currency.Currency { return &n.Fees.Emoluments }},
+	{label: "taxa operacional", target: func(n *Note) *currency.Currency { return &n.Fees.Brokerage }},
+	{label: "corretagem", target: func(n *Note) *currency.Currency { return &n.Fees.Brokerage }},
+	{label: "execucao", target: func(n *Note) *currency.Currency { return &n.Fees.Brokerage }},
+	{label: "taxa de custodia", target: func(n *Note) *currency.Currency { return &n.Fees.Brokerage }},
+	{label: "outras", target: func(n *Note) *currency.Currency { return &n.Fees.Brokerage }},
+	{label: "iss", target: func(n *Note) *currency.Currency { return &n.Fees.ISS }},
+	{label: "impostos", target: func(n *Note) *currency.Currency { return &n.Fees.ISS }},
+	{label: "i.r.r.f. s/ operacoes", target: func(n *Note) *currency.Currency { return &n.WithheldTax }},
+}
+
+func ExtractText(ctx context.Context, data []byte) (string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		return string(data), nil
+	}
+
+	cmd := exec.CommandContext(ctx, "pdftotext", "-layout", "-", "-")
+	cmd.Stdin = bytes.NewReader(data)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", errors.New("pdftotext not found: install poppler-utils or import the note as text")
+	} else if err != nil {
+		return "", fmt.Errorf("pdftotext: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return string(output), nil
+}
+
+func Parse(text string) ([]Note, error) {
+	pages := headerPattern.FindAllStringSubmatchIndex(text, -1)
+	if len(pages) == 0 {
+		return nil, errors.New("no brokerage note found")
+	}
+
+	var notes []Note
+	index := map[string]int{}
+
+	for i, page := range pages {
+		end := len(text)
+		if i+1 < len(pages) {
+			end = pages[i+1][0]
+		}
+
+		number := text[page[2]:page[3]]
+		date, err := time.Parse(dateLayout, text[page[4]:page[5]])
+		if err != nil {
+			return nil, fmt.Errorf("note %s: invalid date %s", number, text[page[4]:page[5]])
+		}
+
+		n, ok := index[number]
+		if !ok {
+			n = len(notes)
+			index[number] = n
+			notes = append(notes, Note{Number: number, Date: date})
+		}
+
+		if err := notes[n].parse(text[page[1]:end]); err != nil {
+			return nil, fmt.Errorf("note %s: %w", number, err)
+		}
+	}
+
+	return notes, nil
+}
+
+func (n *Note) parse(page string) error {
+	var summary Note
+	var hasSummary bool
+
+	for _, line := range strings.Split(page, "\n") {
+		if match := tradePattern.FindStringSubmatch(line); match != nil {
+			trade, ok, err := newTrade(match)
+			if err != nil {
+				return err
+			} else if ok {
+				n.Trades = append(n.Trades, trade)
+			}
+
+			continue
+		}
+
+		label := normalize(line)
+		for _, f := range fees {
+			if !strings.HasPrefix(label, f.label) {
+				continue
+			}
+
+			amount := amountPattern.FindStringSubmatch(line)
+			if amount == nil {
+				break
+			}
+
+			value, err := currency.Parse(amount[1])
+			if err != nil {
+				return err
+			}
+
+			target := f.target(&summary)
+			*target = target.Add(value)
+			hasSummary = true
+			break
+		}
+	}
+
+	if hasSummary {
+		n.Fees, n.WithheldTax = summary.Fees, summary.WithheldTax
+	}
+
+	return nil
+}
+
+func newTrade(match []string) (Trade, bool, error) {
+	t := operation.Buy
+	if match[1] == "V" {
+		t = operation.Sell
+	}
+
+	market, specification := splitMarket(match[2])
+	if market == "" {
+		return Trade{}, false, nil
+	}
+
+	quantity, err := strconv.Atoi(strings.ReplaceAll(match[3], ".", ""))
+	if err != nil {
+		return Trade{}, false, fmt.Errorf("invalid quantity %s", match[3])
+	}
+
+	price, err := currency.Parse(match[4])
+	if err != nil {
+		return Trade{}, false, err
+	}
+
+	value, err := currency.Parse(match[5])
+	if err != nil {
+		return Trade{}, false, err
+	}
+
+	return Trade{
+		Type:          t,
+		Specification: specification,
+		Quantity:      quantity,
+		Price:         price,
+		Value:         value,
+	}, true, nil
+}
+
+func splitMarket(raw string) (string, string) {
+	fields := strings.Fields(raw)
+	if len(fields) < 2 {
+		return "", ""
+	}
+
+	market := strings.ToUpper(accents.Replace(fields[0]))
+	for _, spot := range spotMarkets {
+		if market != spot {
+			continue
+		}
+
+		specification := fields[1:]
+		if len(specification) > 1 && observations[specification[len(specification)-1]] {
+			specification = specification[:len(specification)-1]
+		}
+
+		return market, strings.Join(specification, " ")
+	}
+
+	return "", ""
+}
+
+func (n Note) Operations(symbols map[string]stock.Symbol) (operation.List, error) {
+	var total, sold currency.Currency
+	for _, trade := range n.Trades {
+		total = total.Add(trade.Value)
+		if trade.Type == operation.Sell {
+			sold = sold.Add(trade.Value)
+		}
+	}
+
+	operations := make(operation.List, len(n.Trades))
+	var allocated Note
+
+	for i, trade := range n.Trades {
+		symbol, err := resolve(trade.Specification, symbols)
+		if err != nil {
+			return nil, err
+		}
+
+		last := i == len(n.Trades)-1
+		fees := operation.Fees{
+			Brokerage:  apportion(n.Fees.Brokerage, &allocated.Fees.Brokerage, trade.Value, total, last),
+			Emoluments: apportion(n.Fees.Emoluments, &allocated.Fees.Emoluments, trade.Value, total, last),
+			Settlement: apportion(n.Fees.Settlement, &allocated.Fees.Settlement, trade.Value, total, last),
+			ISS:        apportion(n.Fees.ISS, &allocated.Fees.ISS, trade.Value, total, last),
+		}
+
+		var withheldTax currency.Currency
+		if trade.Type == operation.Sell {
+			withheldTax = apportion(n.WithheldTax, &allocated.WithheldTax, trade.Value, sold, n.lastSale(i))
+		}
+
+		operations[i] = operation.Operation{
+			Symbol:      symbol,
+			Type:        trade.Type,
+			Quantity:    trade.Quantity,
+			UnitValue:   trade.Price,
+			Fees:        fees,
+			WithheldTax: withheldTax,
+			Date:        n.Date,
+			Note:        n.Number,
+		}
+	}
+
+	return operations, nil
+}
+
+func (n Note) lastSale(i int) bool {
+	for _, trade := range n.Trades[i+1:] {
+		if trade.Type == operation.Sell {
+			return false
+		}
+	}
+
+	return true
+}
+
+func apportion(amount currency.Currency, allocated *currency.Currency, value, total currency.Currency, last bool) currency.Currency {
+	share := amount.MulRatio(value.Cents(), total.Cents())
+	if last {
+		share = amount.Sub(*allocated)
+	}
+
+	*allocated = allocated.Add(share)
+	return share
+}
+
+func resolve(specification string, symbols map[string]stock.Symbol) (stock.Symbol, error) {
+	if symbol, ok := symbols[normalizeSpecification(specification)]; ok {
+		return symbol, nil
+	}
+
+	if match := tickerPattern.FindStringSubmatch(specification); match != nil {
+		return stock.Symbol(match[1]), nil
+	}
+
+	return "", fmt.Errorf("unknown symbol for %q: add it to the symbols file", specification)
+}
+
+func ReadSymbols(reader io.Reader) (map[string]stock.Symbol, error) {
+	entries, err := csv.Import(reader, true, func(elements []string) ([2]string, error) {
+		if len(elements) < 2 || elements[0] == "" || elements[1] == "" {
+			return [2]string{}, errors.New("expected Specification,Symbol")
+		}
+
+		return [2]string{elements[0], elements[1]}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make(map[string]stock.Symbol, len(entries))
+	for _, entry := range entries {
+		symbols[normalizeSpecification(entry[0])] = stock.Symbol(strings.ToUpper(strings.TrimSpace(entry[1])))
+	}
+
+	return symbols, nil
+}
+
+func normalize(raw string) string {
+	return accents.Replace(strings.ToLower(strings.TrimSpace(raw)))
+}
+
+func normalizeSpecification(raw string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(accents.Replace(raw))), " ")
+}
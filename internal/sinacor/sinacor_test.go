@@ -0,0 +1,175 @@
+package sinacor
+
+import (
+	"reflect"
+	"stocks/currency"
+	"stocks/operation"
+	"stocks/stock"
+	"strings"
+	"testing"
+	"time"
+)
+
+const note = `
+                                         NOTA DE NEGOCIAÇÃO
+  Nr. nota        Folha      Data pregão
+  12345           1          02/05/2022
+  CLEAR CORRETORA - GRUPO XP
+
+Negócios realizados
+Q Negociação   C/V Tipo mercado   Prazo   Especificação do título           Obs. (*)  Quantidade   Preço / Ajuste   Valor Operação / Ajuste   D/C
+1-BOVESPA      C   VISTA                  PETROBRAS PN N2                   #         100          33,50            3.350,00                  D
+1-BOVESPA      V   FRACIONARIO            ITSA4F ITAUSA PN N1                         7            9,10             63,70                     C
+1-BOVESPA      C   OPCAO DE COMPRA 05/22  PETRE350 PN 35,00                           100          0,50             50,00                     D
+                                                                                                    CONTINUA...
+                                         NOTA DE NEGOCIAÇÃO
+  Nr. nota        Folha      Data pregão
+  12345           2          02/05/2022
+
+1-BOVESPA      V   VISTA                  ITAUSA PN N1                      D         3            9,15             27,45                     C
+
+Resumo Financeiro
+Clearing
+Valor líquido das operações                                               3.258,85 D
+Taxa de liquidação                                                             0,95 D
+Taxa de Registro                                                               0,00 D
+Bolsa
+Emolumentos                                                                    0,17 D
+Custos Operacionais
+Taxa Operacional                                                               4,90 D
+ISS (SÃO PAULO)                                                                0,25 D
+I.R.R.F. s/ operações, base R$91,15                                            0,01
+Líquido para 04/05/2022                                                    3.265,12 D
+`
+
+func TestParse(t *testing.T) {
+	date := time.Date(2022, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		text string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []Note
+		wantErr bool
+	}{
+		{
+			name: "Should parse trades and fees of every page",
+			args: args{
+				text: note,
+			},
+			want: []Note{
+				{
+					Number: "12345",
+					Date:   date,
+					Trades: []Trade{
+						{Type: operation.Buy, Specification: "PETROBRAS PN N2", Quantity: 100, Price: currency.New(3350), Value: currency.New(335000)},
+						{Type: operation.Sell, Specification: "ITSA4F ITAUSA PN N1", Quantity: 7, Price: currency.New(910), Value: currency.New(6370)},
+						{Type: operation.Sell, Specification: "ITAUSA PN N1", Quantity: 3, Price: currency.New(915), Value: currency.New(2745)},
+					},
+					Fees: operation.Fees{
+						Brokerage:  currency.New(490),
+						Emoluments: currency.New(17),
+						Settlement: currency.New(95),
+						ISS:        currency.New(25),
+					},
+					WithheldTax: currency.New(1),
+				},
+			},
+		},
+		{
+			name: "Should return error if there is no note",
+			args: args{
+				text: "Extrato de conta corrente",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNote_Operations(t *testing.T) {
+	date := time.Date(2022, 5, 2, 0, 0, 0, 0, time.UTC)
+	n := Note{
+		Number: "12345",
+		Date:   date,
+		Trades: []Trade{
+			{Type: operation.Buy, Specification: "PETROBRAS PN N2", Quantity: 100, Price: currency.New(3000), Value: currency.New(300000)},
+			{Type: operation.Sell, Specification: "ITSA4F ITAUSA PN N1", Quantity: 100, Price: currency.New(1000), Value: currency.New(100000)},
+			{Type: operation.Sell, Specification: "ITAUSA PN N1", Quantity: 100, Price: currency.New(1000), Value: currency.New(100000)},
+		},
+		Fees: operation.Fees{
+			Brokerage:  currency.New(1000),
+			Emoluments: currency.New(101),
+		},
+		WithheldTax: currency.New(3),
+	}
+
+	type args struct {
+		symbols map[string]stock.Symbol
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    operation.List
+		wantErr bool
+	}{
+		{
+			name: "Should apportion fees by trade value",
+			args: args{
+				symbols: map[string]stock.Symbol{"PETROBRAS PN N2": "PETR4", "ITAUSA PN N1": "ITSA4"},
+			},
+			want: operation.List{
+				{Symbol: "PETR4", Type: operation.Buy, Quantity: 100, UnitValue: currency.New(3000), Date: date, Note: "12345",
+					Fees: operation.Fees{Brokerage: currency.New(600), Emoluments: currency.New(61)}},
+				{Symbol: "ITSA4", Type: operation.Sell, Quantity: 100, UnitValue: currency.New(1000), Date: date, Note: "12345",
+					Fees: operation.Fees{Brokerage: currency.New(200), Emoluments: currency.New(20)}, WithheldTax: currency.New(2)},
+				{Symbol: "ITSA4", Type: operation.Sell, Quantity: 100, UnitValue: currency.New(1000), Date: date, Note: "12345",
+					Fees: operation.Fees{Brokerage: currency.New(200), Emoluments: currency.New(20)}, WithheldTax: currency.New(1)},
+			},
+		},
+		{
+			name: "Should return error if symbol is unknown",
+			args: args{
+				symbols: map[string]stock.Symbol{"ITAUSA PN N1": "ITSA4"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := n.Operations(tt.args.symbols)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Operations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Operations() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSymbols(t *testing.T) {
+	got, err := ReadSymbols(strings.NewReader("Specification,Symbol\nPetrobras  PN N2,petr4\n"))
+	if err != nil {
+		t.Fatalf("ReadSymbols() error = %v", err)
+	}
+
+	want := map[string]stock.Symbol{"PETROBRAS PN N2": "PETR4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSymbols() got = %v, want %v", got, want)
+	}
+}
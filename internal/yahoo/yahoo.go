@@ -0,0 +1,179 @@
+package yahoo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"stocks/currency"
+	"stocks/internal/httpjson"
+	"stocks/stock"
+	"time"
+)
+
+const (
+	baseUrl = "https://query1.finance.yahoo.com/v8/finance/chart"
+	suffix  = ".SA"
+)
+
+type (
+	Meta struct {
+		Symbol             string  `json:"symbol"`
+		LongName           string  `json:"longName"`
+		ShortName          string  `json:"shortName"`
+		RegularMarketPrice float64 `json:"regularMarketPrice"`
+		PreviousClose      float64 `json:"chartPreviousClose"`
+	}
+
+	Quote struct {
+		Open   []*float64 `json:"open"`
+		High   []*float64 `json:"high"`
+		Low    []*float64 `json:"low"`
+		Close  []*float64 `json:"close"`
+		Volume []*int64   `json:"volume"`
+	}
+
+	Result struct {
+		Meta       Meta    `json:"meta"`
+		Timestamp  []int64 `json:"timestamp"`
+		Indicators struct {
+			Quote []Quote `json:"quote"`
+		} `json:"indicators"`
+	}
+
+	Response struct {
+		Chart struct {
+			Result []Result `json:"result"`
+		} `json:"chart"`
+	}
+
+	Provider struct {
+		Client httpjson.Client
+	}
+)
+
+func NewProvider(client httpjson.Client) *Provider {
+	return &Provider{
+		Client: client,
+	}
+}
+
+func (p Provider) LastInfo(ctx context.Context, symbol stock.Symbol) (stock.Info, error) {
+	result, err := p.chart(ctx, symbol, url.Values{"range": {"1d"}, "interval": {"1d"}})
+	if err != nil {
+		return stock.Info{}, err
+	}
+
+	info := stock.Info{
+		Symbol:    symbol,
+		LastPrice: currency.NewFromFloat(result.Meta.RegularMarketPrice),
+	}
+
+	if result.Meta.PreviousClose > 0 {
+		info.Change = (result.Meta.RegularMarketPrice/result.Meta.PreviousClose - 1) * 100
+	}
+
+	if candles := result.candles(symbol); len(candles) > 0 {
+		last := candles[len(candles)-1]
+		info.OpeningPrice, info.MaxPrice, info.MinPrice = last.Open, last.High, last.Low
+	}
+
+	return info, nil
+}
+
+func (p Provider) LastInfos(ctx context.Context, symbols []stock.Symbol) (map[stock.Symbol]stock.Info, error) {
+	return stock.SequentialLastInfos(ctx, p, symbols)
+}
+
+func (p Provider) Sequential() bool {
+	return true
+}
+
+func (p Provider) Details(ctx context.Context, symbol stock.Symbol) (stock.Details, error) {
+	result, err := p.chart(ctx, symbol, url.Values{"range": {"1d"}, "interval": {"1d"}})
+	if err != nil {
+		return stock.Details{}, err
+	}
+
+	name := result.Meta.LongName
+	if name == "" {
+		name = result.Meta.ShortName
+	}
+
+	return stock.Details{
+		Symbol: symbol,
+		Class:  stock.Unclassified,
+		Name:   name,
+	}, nil
+}
+
+func (p Provider) History(ctx context.Context, symbol stock.Symbol, from, to time.Time) (stock.Candles, error) {
+	query := url.Values{
+		"period1":  {fmt.Sprint(from.Unix())},
+		"period2":  {fmt.Sprint(to.AddDate(0, 0, 1).Unix())},
+		"interval": {"1d"},
+	}
+
+	result, err := p.chart(ctx, symbol, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.candles(symbol).Between(from, to), nil
+}
+
+func (p Provider) chart(ctx context.Context, symbol stock.Symbol, query url.Values) (Result, error) {
+	address := fmt.Sprintf("%s/%s?%s", baseUrl, url.PathEscape(string(symbol)+suffix), query.Encode())
+
+	response, err := httpjson.Get[Response](ctx, p.Client, address)
+	if err != nil {
+		return Result{}, err
+	} else if len(response.Chart.Result) == 0 {
+		return Result{}, fmt.Errorf("%s not found", symbol)
+	}
+
+	return response.Chart.Result[0], nil
+}
+
+func (r Result) candles(symbol stock.Symbol) stock.Candles {
+	if len(r.Indicators.Quote) == 0 {
+		return nil
+	}
+
+	quote := r.Indicators.Quote[0]
+
+	var candles stock.Candles
+	for i, timestamp := range r.Timestamp {
+		if i >= len(quote.Close) || quote.Close[i] == nil {
+			continue
+		}
+
+		date := time.Unix(timestamp, 0).UTC()
+		candles = append(candles, stock.Candle{
+			Symbol: symbol,
+			Date:   time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+			Open:   price(quote.Open, i),
+			High:   price(quote.High, i),
+			Low:    price(quote.Low, i),
+			Close:  price(quote.Close, i),
+			Volume: volume(quote.Volume, i),
+		})
+	}
+
+	return candles
+}
+
+func price(values []*float64, i int) currency.Currency {
+	if i >= len(values) || values[i] == nil {
+		return currency.Currency{}
+	}
+
+	return currency.NewFromFloat(*values[i])
+}
+
+func volume(values []*int64, i int) int64 {
+	if i >= len(values) || values[i] == nil {
+		return 0
+	}
+
+	return *values[i]
+}
@@ -0,0 +1,130 @@
+package yahoo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"stocks/currency"
+	"stocks/internal/httpjson"
+	"stocks/stock"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeClient map[string]string
+
+func (c fakeClient) Do(r *http.Request) (*http.Response, error) {
+	body, ok := c[r.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestProvider_LastInfo(t *testing.T) {
+	client := fakeClient{
+		baseUrl + "/STOCK1.SA?interval=1d&range=1d": `{"chart":{"result":[{"meta":{"symbol":"STOCK1.SA","regularMarketPrice":10,
+			"chartPreviousClose":8},"timestamp":[1650891600],"indicators":{"quote":[{"open":[9.5],"high":[10.5],"low":[9.2],
+			"close":[10],"volume":[1000]}]}}]}}`,
+		baseUrl + "/NONE3.SA?interval=1d&range=1d": `{"chart":{"result":[]}}`,
+	}
+
+	tests := []struct {
+		name    string
+		symbol  stock.Symbol
+		want    stock.Info
+		wantErr error
+	}{
+		{
+			name:   "Should query the B3 suffix and decode the last candle",
+			symbol: "STOCK1",
+			want: stock.Info{Symbol: "STOCK1", OpeningPrice: currency.NewFromFloat(9.5), MaxPrice: currency.NewFromFloat(10.5),
+				MinPrice: currency.NewFromFloat(9.2), LastPrice: currency.NewFromFloat(10), Change: 25},
+		},
+		{
+			name:    "Should return error if symbol is not found",
+			symbol:  "NONE3",
+			wantErr: errors.New("NONE3 not found"),
+		},
+		{
+			name:    "Should return temporary status errors",
+			symbol:  "FAIL3",
+			wantErr: httpjson.StatusError{StatusCode: http.StatusTooManyRequests},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewProvider(client).LastInfo(context.Background(), tt.symbol)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("LastInfo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LastInfo() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvider_LastInfos(t *testing.T) {
+	client := fakeClient{
+		baseUrl + "/STOCK1.SA?interval=1d&range=1d": `{"chart":{"result":[{"meta":{"symbol":"STOCK1.SA","regularMarketPrice":11}}]}}`,
+		baseUrl + "/NONE3.SA?interval=1d&range=1d":  `{"chart":{"result":[]}}`,
+	}
+
+	got, err := NewProvider(client).LastInfos(context.Background(), []stock.Symbol{"STOCK1", "NONE3"})
+
+	want := map[stock.Symbol]stock.Info{"STOCK1": {Symbol: "STOCK1", LastPrice: currency.NewFromFloat(11)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LastInfos() got = %v, want %v", got, want)
+	}
+
+	if failed := stock.Failed(err, nil); len(failed) != 1 || failed["NONE3"] == nil {
+		t.Errorf("LastInfos() error = %v, want failure for NONE3 only", err)
+	}
+}
+
+func TestProvider_Details(t *testing.T) {
+	client := fakeClient{
+		baseUrl + "/STOCK1.SA?interval=1d&range=1d": `{"chart":{"result":[{"meta":{"symbol":"STOCK1.SA","shortName":"STOCK ONE ON"}}]}}`,
+	}
+
+	got, err := NewProvider(client).Details(context.Background(), "STOCK1")
+	if err != nil {
+		t.Fatalf("Details() error = %v", err)
+	}
+
+	if want := (stock.Details{Symbol: "STOCK1", Class: stock.Unclassified, Name: "STOCK ONE ON"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Details() got = %v, want %v", got, want)
+	}
+}
+
+func TestProvider_History(t *testing.T) {
+	client := fakeClient{
+		baseUrl + "/STOCK1.SA?interval=1d&period1=1650844800&period2=1651104000": `{"chart":{"result":[{"meta":{"symbol":"STOCK1.SA"},
+			"timestamp":[1650891600,1650978000,1651064400],"indicators":{"quote":[{"open":[10,null,12],"high":[11,null,13],
+			"low":[9,null,11],"close":[10.5,null,12.5],"volume":[100,null,300]}]}}]}}`,
+	}
+
+	from := time.Date(2022, 4, 25, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)
+
+	got, err := NewProvider(client).History(context.Background(), "STOCK1", from, to)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+
+	want := stock.Candles{
+		{Symbol: "STOCK1", Date: from, Open: currency.NewFromFloat(10), High: currency.NewFromFloat(11), Low: currency.NewFromFloat(9),
+			Close: currency.NewFromFloat(10.5), Volume: 100},
+		{Symbol: "STOCK1", Date: to, Open: currency.NewFromFloat(12), High: currency.NewFromFloat(13), Low: currency.NewFromFloat(11),
+			Close: currency.NewFromFloat(12.5), Volume: 300},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("History() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,282 @@
+package irpf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"stocks/currency"
+	"stocks/income"
+	"stocks/operation"
+	"stocks/separator"
+	"stocks/stock"
+	"stocks/tax"
+	"strings"
+	"time"
+)
+
+const (
+	ExemptGainCode = "20"
+
+	dateLayout  = "02/01/2006"
+	monthLayout = "2006-01"
+)
+
+type (
+	Good struct {
+		Symbol       stock.Symbol
+		Class        stock.Class
+		Name         string
+		CNPJ         string
+		Quantity     int
+		AveragePrice currency.Currency
+		Previous     currency.Currency
+		Current      currency.Currency
+	}
+
+	Income struct {
+		Type   income.Type
+		Symbol stock.Symbol
+		Name   string
+		CNPJ   string
+		Amount currency.Currency
+	}
+
+	Report struct {
+		Year    int
+		Goods   []Good
+		Incomes []Income
+		Months  []tax.Report
+	}
+)
+
+func Calculate(operations operation.List, incomes income.List, details map[stock.Symbol]stock.Details, year int) Report {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	report := Report{
+		Year:    year,
+		Goods:   goods(operations, details, from.AddDate(0, 0, -1), to.AddDate(0, 0, -1)),
+		Incomes: grouped(incomes.Between(from, to), details),
+	}
+
+	classes := map[stock.Symbol]stock.Class{}
+	for symbol, d := range details {
+		classes[symbol] = d.Class
+	}
+
+	for month := from; month.Before(to); month = month.AddDate(0, 1, 0) {
+		report.Months = append(report.Months, tax.Calculate(operations, classes, month))
+	}
+
+	return report
+}
+
+func (g Good) Group() string {
+	if g.Class == stock.RealEstateFund {
+		return "07"
+	}
+
+	return "03"
+}
+
+func (g Good) Code() string {
+	if g.Class == stock.RealEstateFund {
+		return "03"
+	}
+
+	return "01"
+}
+
+func (g Good) Discrimination() string {
+	unit := "ações"
+	if g.Class == stock.RealEstateFund {
+		unit = "cotas"
+	}
+
+	description := fmt.Sprintf("%d %s %s - %s", g.Quantity, unit, g.Symbol, g.Name)
+	if g.CNPJ != "" {
+		description = fmt.Sprintf("%s - CNPJ %s", description, g.CNPJ)
+	}
+
+	return fmt.Sprintf("%s - preço médio %s", description, g.AveragePrice)
+}
+
+func (i Income) Section() string {
+	if i.Type == income.InterestOnEquity {
+		return "Exclusivos"
+	}
+
+	return "Isentos"
+}
+
+func (i Income) Code() string {
+	switch i.Type {
+	case income.Dividend:
+		return "09"
+	case income.InterestOnEquity:
+		return "10"
+	default:
+		return "26"
+	}
+}
+
+func (r Report) Total(t income.Type) currency.Currency {
+	var total currency.Currency
+
+	for _, i := range r.Incomes {
+		if i.Type == t {
+			total = total.Add(i.Amount)
+		}
+	}
+
+	return total
+}
+
+func (r Report) ExemptGains() currency.Currency {
+	var total currency.Currency
+
+	for _, month := range r.Months {
+		total = total.Add(month.SwingTrade.ExemptGain)
+	}
+
+	return total
+}
+
+func (r Report) Print(writer io.Writer, sep separator.Separator) error {
+	previous := time.Date(r.Year-1, time.December, 31, 0, 0, 0, 0, time.UTC).Format(dateLayout)
+	current := time.Date(r.Year, time.December, 31, 0, 0, 0, 0, time.UTC).Format(dateLayout)
+
+	title := fmt.Sprintf("Bens e Direitos\nGroup%sCode%sSymbol%sCNPJ%sDiscrimination%s%s%s%s\n",
+		sep, sep, sep, sep, sep, previous, sep, current)
+	if _, err := io.WriteString(writer, title); err != nil {
+		return err
+	}
+
+	for _, good := range r.Goods {
+		line := fmt.Sprintf("%s%s%s%s%s%s%s%s%s%s%s%s%s\n", good.Group(), sep, good.Code(), sep, good.Symbol, sep,
+			good.CNPJ, sep, good.Discrimination(), sep, value(good.Previous), sep, value(good.Current))
+
+		if _, err := io.WriteString(writer, line); err != nil {
+			return err
+		}
+	}
+
+	title = fmt.Sprintf("\nRendimentos\nSection%sCode%sSymbol%sCNPJ%sName%sValue\n", sep, sep, sep, sep, sep)
+	if _, err := io.WriteString(writer, title); err != nil {
+		return err
+	}
+
+	for _, i := range r.Incomes {
+		line := fmt.Sprintf("%s%s%s%s%s%s%s%s%s%s%s\n", i.Section(), sep, i.Code(), sep, i.Symbol, sep, i.CNPJ, sep,
+			i.Name, sep, value(i.Amount))
+
+		if _, err := io.WriteString(writer, line); err != nil {
+			return err
+		}
+	}
+
+	totals := fmt.Sprintf("\nTotals\nDividends (09)%s%s\nFII (26)%s%s\nSwing Trade Exempt Gains (%s)%s%s\nJCP (10)%s%s\n",
+		sep, value(r.Total(income.Dividend)), sep, value(r.Total(income.FundIncome)), ExemptGainCode, sep,
+		value(r.ExemptGains()), sep, value(r.Total(income.InterestOnEquity)))
+	if _, err := io.WriteString(writer, totals); err != nil {
+		return err
+	}
+
+	title = fmt.Sprintf("\nRenda Variável\nMonth%sSwing Trade%sDay Trade%sFII%sSwing Trade Loss%sDay Trade Loss%sFII Loss%sIRRF%sDARF %s\n",
+		sep, sep, sep, sep, sep, sep, sep, sep, tax.DarfCode)
+	if _, err := io.WriteString(writer, title); err != nil {
+		return err
+	}
+
+	for _, month := range r.Months {
+		line := fmt.Sprintf("%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s\n", month.Period.Format(monthLayout), sep,
+			value(month.SwingTrade.Gain), sep, value(month.DayTrade.Gain), sep, value(month.RealEstate.Gain), sep,
+			value(month.SwingTrade.CarriedLoss), sep, value(month.DayTrade.CarriedLoss), sep,
+			value(month.RealEstate.CarriedLoss), sep, value(month.WithheldTax), sep, value(month.Due))
+
+		if _, err := io.WriteString(writer, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func goods(operations operation.List, details map[stock.Symbol]stock.Details, previous, current time.Time) []Good {
+	costs := map[stock.Symbol]currency.Currency{}
+	for _, position := range operations.Until(previous).Positions() {
+		costs[position.Symbol] = position.Cost
+	}
+
+	var output []Good
+	for _, position := range operations.Until(current).Positions() {
+		good := Good{
+			Symbol:       position.Symbol,
+			Class:        details[position.Symbol].Class,
+			Name:         name(details, position.Symbol),
+			CNPJ:         details[position.Symbol].CNPJ,
+			Quantity:     position.Quantity,
+			AveragePrice: position.AveragePrice,
+			Previous:     costs[position.Symbol],
+			Current:      position.Cost,
+		}
+
+		if good.Previous.IsZero() && good.Current.IsZero() {
+			continue
+		}
+
+		output = append(output, good)
+	}
+
+	return output
+}
+
+func grouped(incomes income.List, details map[stock.Symbol]stock.Details) []Income {
+	var output []Income
+	index := map[string]int{}
+
+	for _, i := range incomes {
+		key := fmt.Sprintf("%s/%s", i.Type, i.Symbol)
+
+		n, ok := index[key]
+		if !ok {
+			n = len(output)
+			index[key] = n
+			output = append(output, Income{
+				Type:   i.Type,
+				Symbol: i.Symbol,
+				Name:   name(details, i.Symbol),
+				CNPJ:   details[i.Symbol].CNPJ,
+			})
+		}
+
+		amount := i.Amount
+		if i.Type == income.InterestOnEquity {
+			amount = i.Net()
+		}
+
+		output[n].Amount = output[n].Amount.Add(amount)
+	}
+
+	sort.SliceStable(output, func(i, j int) bool {
+		if output[i].Type == output[j].Type {
+			return output[i].Symbol < output[j].Symbol
+		}
+
+		return output[i].Type < output[j].Type
+	})
+
+	return output
+}
+
+func name(details map[stock.Symbol]stock.Details, symbol stock.Symbol) string {
+	if name := details[symbol].Name; name != "" {
+		return name
+	}
+
+	return string(symbol)
+}
+
+func value(c currency.Currency) string {
+	return strings.Replace(c.Decimal(), ".", ",", 1)
+}
@@ -0,0 +1,111 @@
+package irpf
+
+import (
+	"reflect"
+	"stocks/currency"
+	"stocks/income"
+	"stocks/operation"
+	"stocks/stock"
+	"testing"
+	"time"
+)
+
+func TestCalculate(t *testing.T) {
+	details := map[stock.Symbol]stock.Details{
+		"STOCK1": {Symbol: "STOCK1", Class: stock.Stock, Name: "Stock One S.A.", CNPJ: "33.000.167/0001-01"},
+		"FUND11": {Symbol: "FUND11", Class: stock.RealEstateFund, Name: "Fund Eleven FII"},
+	}
+
+	type args struct {
+		operations operation.List
+		incomes    income.List
+		year       int
+	}
+	tests := []struct {
+		name            string
+		args            args
+		wantGoods       []Good
+		wantIncomes     []Income
+		wantExemptGains currency.Currency
+	}{
+		{
+			name: "Should report acquisition cost at the end of both years",
+			args: args{
+				operations: operation.List{
+					{Symbol: "STOCK1", Type: operation.Buy, Quantity: 100, UnitValue: currency.NewFromFloat(10), Date: time.Date(2021, 6, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Buy, Quantity: 100, UnitValue: currency.NewFromFloat(20), Date: time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Sell, Quantity: 50, UnitValue: currency.NewFromFloat(30), Date: time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "FUND11", Type: operation.Buy, Quantity: 10, UnitValue: currency.NewFromFloat(100), Date: time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK2", Type: operation.Buy, Quantity: 10, UnitValue: currency.NewFromFloat(5), Date: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+				},
+				year: 2022,
+			},
+			wantGoods: []Good{
+				{Symbol: "FUND11", Class: stock.RealEstateFund, Name: "Fund Eleven FII", Quantity: 10, AveragePrice: currency.NewFromFloat(100),
+					Current: currency.NewFromFloat(1000)},
+				{Symbol: "STOCK1", Class: stock.Stock, Name: "Stock One S.A.", CNPJ: "33.000.167/0001-01", Quantity: 150,
+					AveragePrice: currency.NewFromFloat(15), Previous: currency.NewFromFloat(1000), Current: currency.NewFromFloat(2250)},
+			},
+			wantExemptGains: currency.NewFromFloat(750),
+		},
+		{
+			name: "Should not report real estate fund gains as exempt",
+			args: args{
+				operations: operation.List{
+					{Symbol: "FUND11", Type: operation.Buy, Quantity: 10, UnitValue: currency.NewFromFloat(100), Date: time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "FUND11", Type: operation.Sell, Quantity: 10, UnitValue: currency.NewFromFloat(120), Date: time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC)},
+				},
+				year: 2022,
+			},
+		},
+		{
+			name: "Should report the exact acquisition cost instead of the rounded average price",
+			args: args{
+				operations: operation.List{
+					{Symbol: "STOCK1", Type: operation.Buy, Quantity: 1, UnitValue: currency.NewFromFloat(10), Date: time.Date(2022, 6, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Buy, Quantity: 2, UnitValue: currency.NewFromFloat(10.01), Date: time.Date(2022, 6, 11, 0, 0, 0, 0, time.UTC)},
+				},
+				year: 2022,
+			},
+			wantGoods: []Good{
+				{Symbol: "STOCK1", Class: stock.Stock, Name: "Stock One S.A.", CNPJ: "33.000.167/0001-01", Quantity: 3,
+					AveragePrice: currency.NewFromFloat(10.01), Current: currency.NewFromFloat(30.02)},
+			},
+		},
+		{
+			name: "Should group incomes of the year by type and symbol",
+			args: args{
+				incomes: income.List{
+					{Symbol: "STOCK1", Type: income.InterestOnEquity, Amount: currency.NewFromFloat(100), WithheldTax: currency.NewFromFloat(15), Date: time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: income.Dividend, Amount: currency.NewFromFloat(20), Date: time.Date(2022, 4, 20, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "FUND11", Type: income.FundIncome, Amount: currency.NewFromFloat(5), Date: time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: income.Dividend, Amount: currency.NewFromFloat(30), Date: time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: income.Dividend, Amount: currency.NewFromFloat(40), Date: time.Date(2023, 1, 20, 0, 0, 0, 0, time.UTC)},
+				},
+				year: 2022,
+			},
+			wantIncomes: []Income{
+				{Type: income.Dividend, Symbol: "STOCK1", Name: "Stock One S.A.", CNPJ: "33.000.167/0001-01", Amount: currency.NewFromFloat(50)},
+				{Type: income.InterestOnEquity, Symbol: "STOCK1", Name: "Stock One S.A.", CNPJ: "33.000.167/0001-01", Amount: currency.NewFromFloat(85)},
+				{Type: income.FundIncome, Symbol: "FUND11", Name: "Fund Eleven FII", Amount: currency.NewFromFloat(5)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Calculate(tt.args.operations, tt.args.incomes, details, tt.args.year)
+			if !reflect.DeepEqual(got.Goods, tt.wantGoods) {
+				t.Errorf("Calculate() goods = %v, want %v", got.Goods, tt.wantGoods)
+			}
+			if !reflect.DeepEqual(got.Incomes, tt.wantIncomes) {
+				t.Errorf("Calculate() incomes = %v, want %v", got.Incomes, tt.wantIncomes)
+			}
+			if got.ExemptGains() != tt.wantExemptGains {
+				t.Errorf("ExemptGains() = %v, want %v", got.ExemptGains(), tt.wantExemptGains)
+			}
+			if len(got.Months) != 12 {
+				t.Errorf("Calculate() months = %d, want 12", len(got.Months))
+			}
+		})
+	}
+}
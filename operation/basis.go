@@ -0,0 +1,252 @@
+package operation
+
+import (
+	"fmt"
+	"math"
+	"stocks/currency"
+	"strings"
+	"time"
+)
+
+const (
+	Average Method = iota
+	FIFO
+	SpecificLot
+)
+
+var (
+	methods = map[string]Method{
+		"average":  Average,
+		"fifo":     FIFO,
+		"specific": SpecificLot,
+	}
+)
+
+type (
+	Method int
+
+	Methods map[string]Method
+
+	Lot struct {
+		Date     time.Time
+		Quantity int
+		Cost     currency.Currency
+	}
+
+	costBasis interface {
+		add(lot Lot)
+		remove(quantity int, preferred []time.Time) (currency.Currency, []Lot)
+		apply(event Operation) currency.Currency
+		quantity() int
+		cost() currency.Currency
+		open() []Lot
+	}
+
+	averageCost struct {
+		shares int
+		total  currency.Currency
+	}
+
+	lotCost struct {
+		lots []Lot
+	}
+)
+
+func ParseMethod(raw string) (Method, error) {
+	method, ok := methods[strings.ToLower(raw)]
+	if !ok {
+		return Average, fmt.Errorf("unknown cost basis method %q: expected average, fifo or specific", raw)
+	}
+
+	return method, nil
+}
+
+func (m Method) String() string {
+	switch m {
+	case FIFO:
+		return "fifo"
+	case SpecificLot:
+		return "specific"
+	default:
+		return "average"
+	}
+}
+
+func (m Methods) of(portfolio string) Method {
+	return m[portfolio]
+}
+
+func (m Method) book(portfolio string) string {
+	if m == Average {
+		return ""
+	}
+
+	return portfolio
+}
+
+func newCostBasis(method Method) costBasis {
+	if method == Average {
+		return &averageCost{}
+	}
+
+	return &lotCost{}
+}
+
+func (a *averageCost) add(lot Lot) {
+	a.shares += lot.Quantity
+	a.total = a.total.Add(lot.Cost)
+}
+
+func (a *averageCost) remove(quantity int, _ []time.Time) (currency.Currency, []Lot) {
+	if quantity >= a.shares {
+		cost := a.total
+		a.shares, a.total = 0, currency.Currency{}
+		return cost, nil
+	}
+
+	cost := a.total.MulRatio(int64(quantity), int64(a.shares))
+	a.shares -= quantity
+	a.total = a.total.Sub(cost)
+
+	return cost, nil
+}
+
+func (a *averageCost) apply(event Operation) currency.Currency {
+	shares := eventQuantity(a.shares, event.Ratio)
+
+	var cost currency.Currency
+	if event.Type == Bonus && shares > a.shares {
+		cost = event.UnitValue.Mul(shares - a.shares)
+		a.total = a.total.Add(cost)
+	}
+
+	a.shares = shares
+	if a.shares <= 0 {
+		a.total = currency.Currency{}
+	}
+
+	return cost
+}
+
+func (a *averageCost) quantity() int {
+	return a.shares
+}
+
+func (a *averageCost) cost() currency.Currency {
+	return a.total
+}
+
+func (a *averageCost) open() []Lot {
+	return nil
+}
+
+func (l *lotCost) add(lot Lot) {
+	l.lots = append(l.lots, lot)
+}
+
+func (l *lotCost) remove(quantity int, preferred []time.Time) (currency.Currency, []Lot) {
+	var cost currency.Currency
+	var consumed []Lot
+
+	take := func(i int) {
+		shares := l.lots[i].Quantity
+		if quantity < shares {
+			shares = quantity
+		}
+
+		part := l.lots[i].Cost.MulRatio(int64(shares), int64(l.lots[i].Quantity))
+		consumed = append(consumed, Lot{Date: l.lots[i].Date, Quantity: shares, Cost: part})
+		cost = cost.Add(part)
+
+		l.lots[i].Quantity -= shares
+		l.lots[i].Cost = l.lots[i].Cost.Sub(part)
+		quantity -= shares
+	}
+
+	for _, date := range preferred {
+		for i := range l.lots {
+			if quantity > 0 && l.lots[i].Quantity > 0 && l.lots[i].Date.Equal(date) {
+				take(i)
+			}
+		}
+	}
+
+	for i := range l.lots {
+		if quantity > 0 && l.lots[i].Quantity > 0 {
+			take(i)
+		}
+	}
+
+	open := l.lots[:0]
+	for _, lot := range l.lots {
+		if lot.Quantity > 0 {
+			open = append(open, lot)
+		}
+	}
+	l.lots = open
+
+	return cost, consumed
+}
+
+func (l *lotCost) apply(event Operation) currency.Currency {
+	if event.Type == Bonus {
+		held := l.quantity()
+		added := eventQuantity(held, event.Ratio) - held
+		if added <= 0 {
+			return currency.Currency{}
+		}
+
+		cost := event.UnitValue.Mul(added)
+		l.lots = append(l.lots, Lot{Date: event.Date, Quantity: added, Cost: cost})
+		return cost
+	}
+
+	var lots []Lot
+	var before, held int
+	var pending currency.Currency
+	for _, lot := range l.lots {
+		held += lot.Quantity
+		after := eventQuantity(held, event.Ratio)
+		lot.Quantity, before = after-before, after
+
+		switch {
+		case lot.Quantity > 0:
+			lot.Cost = lot.Cost.Add(pending)
+			pending = currency.Currency{}
+			lots = append(lots, lot)
+		case len(lots) > 0:
+			lots[len(lots)-1].Cost = lots[len(lots)-1].Cost.Add(lot.Cost)
+		default:
+			pending = pending.Add(lot.Cost)
+		}
+	}
+	l.lots = lots
+
+	return currency.Currency{}
+}
+
+func (l *lotCost) quantity() int {
+	var shares int
+	for _, lot := range l.lots {
+		shares += lot.Quantity
+	}
+
+	return shares
+}
+
+func (l *lotCost) cost() currency.Currency {
+	var total currency.Currency
+	for _, lot := range l.lots {
+		total = total.Add(lot.Cost)
+	}
+
+	return total
+}
+
+func (l *lotCost) open() []Lot {
+	return append([]Lot(nil), l.lots...)
+}
+
+func eventQuantity(quantity int, ratio float64) int {
+	return int(math.Floor(float64(quantity)*ratio + ratioTolerance))
+}
@@ -0,0 +1,47 @@
+package operation
+
+import (
+	"testing"
+)
+
+func TestParseMethod(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    Method
+		wantErr bool
+	}{
+		{
+			name: "Should parse average method",
+			raw:  "average",
+			want: Average,
+		},
+		{
+			name: "Should parse FIFO method ignoring case",
+			raw:  "FIFO",
+			want: FIFO,
+		},
+		{
+			name: "Should parse specific lot method",
+			raw:  "specific",
+			want: SpecificLot,
+		},
+		{
+			name:    "Should return error for unknown method",
+			raw:     "lifo",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMethod(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseMethod() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseMethod() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package operation
+
+import (
+	"fmt"
+	"io"
+	"stocks/separator"
+	"time"
+)
+
+const (
+	Updated Action = "UPDATE"
+	Deleted Action = "DELETE"
+
+	timestampLayout = "2006-01-02 15:04:05"
+)
+
+type (
+	Action string
+
+	Change struct {
+		Action   Action
+		Previous Operation
+		Date     time.Time
+	}
+
+	Changes []Change
+)
+
+func (c Changes) Print(writer io.Writer, sep separator.Separator) error {
+	title := fmt.Sprintf("Changed At%sAction%s%s", sep, sep, printTitle(sep))
+	if _, err := io.WriteString(writer, title); err != nil {
+		return err
+	}
+
+	for _, change := range c {
+		line := fmt.Sprintf("%s%s%s%s%s", change.Date.Format(timestampLayout), sep, change.Action, sep,
+			printLine(change.Previous, sep))
+
+		if _, err := io.WriteString(writer, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package operation
+
+import (
+	"fmt"
+	"io"
+	"stocks/separator"
+	"stocks/stock"
+	"time"
+)
+
+type (
+	Snapshot struct {
+		Date time.Time
+		Position
+	}
+
+	History []Snapshot
+)
+
+func (l List) History(symbol stock.Symbol) History {
+	return l.Ledger(nil).History(symbol)
+}
+
+func (h History) Print(writer io.Writer, sep separator.Separator) error {
+	title := fmt.Sprintf("Date%sQtd.%sAvg. Price%sInvestment%sSettled%sDay Trade\n", sep, sep, sep, sep, sep)
+	if _, err := io.WriteString(writer, title); err != nil {
+		return err
+	}
+
+	for _, snapshot := range h {
+		line := fmt.Sprintf("%s%s%d%s%s%s%s%s%s%s%s\n", snapshot.Date.Format(dateLayout), sep, snapshot.Quantity, sep,
+			snapshot.AveragePrice, sep, snapshot.Investment, sep, snapshot.Settled, sep, snapshot.DayTrade)
+
+		if _, err := io.WriteString(writer, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package operation
+
+import (
+	"reflect"
+	"stocks/currency"
+	"stocks/stock"
+	"testing"
+	"time"
+)
+
+func TestList_History(t *testing.T) {
+	day1 := time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC)
+	day3 := time.Date(2022, 4, 29, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		symbol stock.Symbol
+	}
+	tests := []struct {
+		name string
+		l    List
+		args args
+		want History
+	}{
+		{
+			name: "Should record position after each trading day",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1},
+				{Symbol: "STOCK2", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(50), Date: day1},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 10, UnitValue: currency.NewFromFloat(30), Date: day2},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day3},
+			},
+			args: args{
+				symbol: "STOCK1",
+			},
+			want: History{
+				{Date: day1, Position: Position{Symbol: "STOCK1", Quantity: 10, AveragePrice: currency.NewFromFloat(10), Cost: currency.NewFromFloat(100), Investment: currency.NewFromFloat(100)}},
+				{Date: day2, Position: Position{Symbol: "STOCK1", Investment: currency.NewFromFloat(100), Settled: currency.NewFromFloat(300), Realized: currency.NewFromFloat(200)}},
+				{Date: day3, Position: Position{Symbol: "STOCK1", Quantity: 10, AveragePrice: currency.NewFromFloat(20), Cost: currency.NewFromFloat(200), Investment: currency.NewFromFloat(300), Settled: currency.NewFromFloat(300), Realized: currency.NewFromFloat(200)}},
+			},
+		},
+		{
+			name: "Should merge trades of the same day across portfolios",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "xp"},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day1, Portfolio: "clear"},
+			},
+			args: args{
+				symbol: "STOCK1",
+			},
+			want: History{
+				{Date: day1, Position: Position{Symbol: "STOCK1", Quantity: 20, AveragePrice: currency.NewFromFloat(15), Cost: currency.NewFromFloat(300), Investment: currency.NewFromFloat(300)}},
+			},
+		},
+		{
+			name: "Should return empty history for unknown symbol",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1},
+			},
+			args: args{
+				symbol: "STOCK2",
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.History(tt.args.symbol); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("History() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
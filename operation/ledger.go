@@ -0,0 +1,258 @@
+package operation
+
+import (
+	"sort"
+	"stocks/currency"
+	"stocks/stock"
+	"time"
+)
+
+type (
+	Ledger struct {
+		holdings  map[stock.Symbol]map[string]*holding
+		histories map[stock.Symbol]History
+		sales     Sales
+		oversold  []Oversell
+	}
+
+	Oversell struct {
+		Symbol    stock.Symbol
+		Date      time.Time
+		Portfolio string
+		Quantity  int
+		Held      int
+	}
+
+	holding struct {
+		basis      costBasis
+		investment currency.Currency
+		settled    currency.Currency
+		realized   currency.Currency
+		dayTrade   currency.Currency
+	}
+)
+
+func (l List) Ledger(methods Methods) Ledger {
+	ledger := Ledger{
+		holdings:  map[stock.Symbol]map[string]*holding{},
+		histories: map[stock.Symbol]History{},
+	}
+
+	for _, s := range l.sessions() {
+		for _, event := range s.events {
+			if event.Type == Conversion {
+				ledger.convert(event, methods)
+				continue
+			}
+
+			for _, h := range ledger.holdings[s.symbol] {
+				h.investment = h.investment.Add(h.basis.apply(event))
+			}
+		}
+
+		if s.boughtQuantity > 0 || s.soldQuantity > 0 || len(s.rights) > 0 || len(s.subscriptions) > 0 {
+			method := methods.of(s.portfolio)
+			book := method.book(s.portfolio)
+			h := ledger.holding(s.symbol, method, book)
+
+			h.receive(s.rights)
+			if sold, held := s.soldQuantity-s.dayTradeQuantity(), h.basis.quantity(); sold > held {
+				ledger.oversold = append(ledger.oversold, Oversell{Symbol: s.symbol, Date: s.date, Portfolio: s.portfolio,
+					Quantity: sold, Held: held})
+			}
+
+			ledger.sales = append(ledger.sales, h.trade(s)...)
+
+			for _, subscription := range s.subscriptions {
+				ledger.subscribe(h, subscription, method, book)
+			}
+		}
+
+		ledger.record(s.symbol, s.date)
+	}
+
+	return ledger
+}
+
+func (l Ledger) Position(symbol stock.Symbol) Position {
+	position := Position{
+		Symbol: symbol,
+	}
+
+	for _, h := range l.holdings[symbol] {
+		position.Quantity += h.basis.quantity()
+		position.Investment = position.Investment.Add(h.investment)
+		position.Settled = position.Settled.Add(h.settled)
+		position.Realized = position.Realized.Add(h.realized)
+		position.DayTrade = position.DayTrade.Add(h.dayTrade)
+		position.Cost = position.Cost.Add(h.basis.cost())
+	}
+
+	position.AveragePrice = position.Cost.Div(position.Quantity)
+	return position
+}
+
+func (l Ledger) Positions() []Position {
+	symbols := make([]string, 0, len(l.holdings))
+	for symbol := range l.holdings {
+		symbols = append(symbols, string(symbol))
+	}
+	sort.Strings(symbols)
+
+	positions := make([]Position, len(symbols))
+	for i, symbol := range symbols {
+		positions[i] = l.Position(stock.Symbol(symbol))
+	}
+
+	return positions
+}
+
+func (l Ledger) Lots(symbol stock.Symbol) []Lot {
+	var lots []Lot
+	for _, h := range l.holdings[symbol] {
+		lots = append(lots, h.basis.open()...)
+	}
+
+	sort.SliceStable(lots, func(i, j int) bool {
+		return lots[i].Date.Before(lots[j].Date)
+	})
+
+	return lots
+}
+
+func (l Ledger) History(symbol stock.Symbol) History {
+	return l.histories[symbol]
+}
+
+func (l Ledger) Sales() Sales {
+	return l.sales
+}
+
+func (l Ledger) Oversold() []Oversell {
+	return l.oversold
+}
+
+func (l Ledger) record(symbol stock.Symbol, date time.Time) {
+	history := l.histories[symbol]
+	snapshot := Snapshot{Date: date, Position: l.Position(symbol)}
+
+	if last := len(history) - 1; last >= 0 && history[last].Date.Equal(date) {
+		history[last] = snapshot
+		return
+	}
+
+	l.histories[symbol] = append(history, snapshot)
+}
+
+func (l Ledger) holding(symbol stock.Symbol, method Method, book string) *holding {
+	books, ok := l.holdings[symbol]
+	if !ok {
+		books = map[string]*holding{}
+		l.holdings[symbol] = books
+	}
+
+	h, ok := books[book]
+	if !ok {
+		h = &holding{basis: newCostBasis(method)}
+		books[book] = h
+	}
+
+	return h
+}
+
+func (l Ledger) subscribe(h *holding, subscription Operation, method Method, book string) {
+	rights := subscription.Quantity
+	if held := h.basis.quantity(); held < rights {
+		rights = held
+	}
+
+	cost, _ := h.basis.remove(rights, nil)
+	h.investment = h.investment.Sub(cost)
+
+	shares := subscription.Shares()
+	cost = cost.Add(subscription.UnitValue.Mul(shares)).Add(subscription.Fees.Total())
+
+	target := l.holding(subscription.Destination(), method, book)
+	target.basis.add(Lot{Date: subscription.Date, Quantity: shares, Cost: cost})
+	target.investment = target.investment.Add(cost)
+
+	l.record(subscription.Destination(), subscription.Date)
+}
+
+func (l Ledger) convert(conversion Operation, methods Methods) {
+	destination := conversion.Destination()
+	if destination == "" {
+		return
+	}
+
+	for book, h := range l.holdings[conversion.Symbol] {
+		quantity := h.basis.quantity()
+		if quantity == 0 {
+			continue
+		}
+
+		cost, lots := h.basis.remove(quantity, nil)
+		if lots == nil {
+			lots = []Lot{{Date: conversion.Date, Quantity: quantity, Cost: cost}}
+		}
+
+		h.investment = h.investment.Sub(cost)
+
+		target := l.holding(destination, methods.of(book), book)
+		for _, lot := range lots {
+			lot.Quantity = eventQuantity(lot.Quantity, conversion.Ratio)
+			target.basis.add(lot)
+		}
+		target.investment = target.investment.Add(cost)
+	}
+
+	l.record(destination, conversion.Date)
+}
+
+func (h *holding) receive(rights []Operation) {
+	for _, right := range rights {
+		cost := right.Total()
+		h.basis.add(Lot{Date: right.Date, Quantity: right.Quantity, Cost: cost})
+		h.investment = h.investment.Add(cost)
+	}
+}
+
+func (h *holding) trade(s *session) Sales {
+	var sales Sales
+
+	dayTrade := s.dayTradeQuantity()
+
+	var dayTradeSale Sale
+	if dayTrade > 0 {
+		dayTradeSale = s.sale(dayTrade)
+		sales = append(sales, dayTradeSale)
+		h.dayTrade = h.dayTrade.Add(dayTradeSale.Result())
+	}
+
+	if sold := s.soldQuantity - dayTrade; sold > 0 {
+		cost, lots := h.basis.remove(sold, s.lots)
+
+		sale := Sale{
+			Symbol:      s.symbol,
+			Date:        s.date,
+			Quantity:    sold,
+			Amount:      s.soldAmount.Sub(dayTradeSale.Amount),
+			Proceeds:    s.soldProceeds.Sub(dayTradeSale.Proceeds),
+			Cost:        cost,
+			WithheldTax: s.withheldTax.Sub(dayTradeSale.WithheldTax),
+			Lots:        lots,
+		}
+		sales = append(sales, sale)
+
+		h.settled = h.settled.Add(sale.Proceeds)
+		h.realized = h.realized.Add(sale.Result())
+	}
+
+	if bought := s.boughtQuantity - dayTrade; bought > 0 {
+		cost := s.boughtCost.Sub(dayTradeSale.Cost)
+		h.investment = h.investment.Add(cost)
+		h.basis.add(Lot{Date: s.date, Quantity: bought, Cost: cost})
+	}
+
+	return sales
+}
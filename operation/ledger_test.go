@@ -0,0 +1,295 @@
+package operation
+
+import (
+	"reflect"
+	"stocks/currency"
+	"testing"
+	"time"
+)
+
+func TestLedger_Position(t *testing.T) {
+	day1 := time.Date(2022, 4, 25, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2022, 4, 26, 0, 0, 0, 0, time.UTC)
+	day3 := time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)
+	day4 := time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		methods Methods
+	}
+	tests := []struct {
+		name      string
+		l         List
+		args      args
+		want      Position
+		wantSales Sales
+	}{
+		{
+			name: "Should consume oldest lots first with FIFO",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "us"},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day2, Portfolio: "us"},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 15, UnitValue: currency.NewFromFloat(30), Date: day3, Portfolio: "us"},
+			},
+			args: args{
+				methods: Methods{"us": FIFO},
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     5,
+				AveragePrice: currency.NewFromFloat(20),
+				Cost:         currency.NewFromFloat(100),
+				Investment:   currency.NewFromFloat(300),
+				Settled:      currency.NewFromFloat(450),
+				Realized:     currency.NewFromFloat(250),
+			},
+			wantSales: Sales{
+				{Symbol: "STOCK1", Date: day3, Quantity: 15, Amount: currency.NewFromFloat(450), Proceeds: currency.NewFromFloat(450), Cost: currency.NewFromFloat(200),
+					Lots: []Lot{{Date: day1, Quantity: 10, Cost: currency.NewFromFloat(100)}, {Date: day2, Quantity: 5, Cost: currency.NewFromFloat(100)}}},
+			},
+		},
+		{
+			name: "Should consume the identified lot with specific identification",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "crypto"},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day2, Portfolio: "crypto"},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 10, UnitValue: currency.NewFromFloat(30), Date: day3, Portfolio: "crypto", Lot: day2},
+			},
+			args: args{
+				methods: Methods{"crypto": SpecificLot},
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     10,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(100),
+				Investment:   currency.NewFromFloat(300),
+				Settled:      currency.NewFromFloat(300),
+				Realized:     currency.NewFromFloat(100),
+			},
+			wantSales: Sales{
+				{Symbol: "STOCK1", Date: day3, Quantity: 10, Amount: currency.NewFromFloat(300), Proceeds: currency.NewFromFloat(300), Cost: currency.NewFromFloat(200),
+					Lots: []Lot{{Date: day2, Quantity: 10, Cost: currency.NewFromFloat(200)}}},
+			},
+		},
+		{
+			name: "Should keep lot portfolios apart from average cost portfolios",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "br"},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day1, Portfolio: "us"},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 5, UnitValue: currency.NewFromFloat(30), Date: day2, Portfolio: "us"},
+			},
+			args: args{
+				methods: Methods{"us": FIFO},
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     15,
+				AveragePrice: currency.New(1333),
+				Cost:         currency.NewFromFloat(200),
+				Investment:   currency.NewFromFloat(300),
+				Settled:      currency.NewFromFloat(150),
+				Realized:     currency.NewFromFloat(50),
+			},
+			wantSales: Sales{
+				{Symbol: "STOCK1", Date: day2, Quantity: 5, Amount: currency.NewFromFloat(150), Proceeds: currency.NewFromFloat(150), Cost: currency.NewFromFloat(100),
+					Lots: []Lot{{Date: day1, Quantity: 5, Cost: currency.NewFromFloat(100)}}},
+			},
+		},
+		{
+			name: "Should apply splits to every lot",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "us"},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day2, Portfolio: "us"},
+				{Symbol: "STOCK1", Type: Split, Ratio: 2, Date: day3},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 15, UnitValue: currency.NewFromFloat(10), Date: day4, Portfolio: "us"},
+			},
+			args: args{
+				methods: Methods{"us": FIFO},
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     25,
+				AveragePrice: currency.NewFromFloat(9),
+				Cost:         currency.NewFromFloat(225),
+				Investment:   currency.NewFromFloat(300),
+				Settled:      currency.NewFromFloat(150),
+				Realized:     currency.NewFromFloat(75),
+			},
+			wantSales: Sales{
+				{Symbol: "STOCK1", Date: day4, Quantity: 15, Amount: currency.NewFromFloat(150), Proceeds: currency.NewFromFloat(150), Cost: currency.NewFromFloat(75),
+					Lots: []Lot{{Date: day1, Quantity: 15, Cost: currency.NewFromFloat(75)}}},
+			},
+		},
+		{
+			name: "Should apply reverse splits to the whole position before splitting it across lots",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 5, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "us"},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 15, UnitValue: currency.NewFromFloat(10), Date: day2, Portfolio: "us"},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 15, UnitValue: currency.NewFromFloat(20), Date: day3, Portfolio: "us"},
+				{Symbol: "STOCK1", Type: ReverseSplit, Ratio: 0.1, Date: day4},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 2, UnitValue: currency.NewFromFloat(150), Date: day4, Portfolio: "us"},
+			},
+			args: args{
+				methods: Methods{"us": FIFO},
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     1,
+				AveragePrice: currency.NewFromFloat(300),
+				Cost:         currency.NewFromFloat(300),
+				Investment:   currency.NewFromFloat(500),
+				Settled:      currency.NewFromFloat(300),
+				Realized:     currency.NewFromFloat(100),
+			},
+			wantSales: Sales{
+				{Symbol: "STOCK1", Date: day4, Quantity: 2, Amount: currency.NewFromFloat(300), Proceeds: currency.NewFromFloat(300), Cost: currency.NewFromFloat(200),
+					Lots: []Lot{{Date: day2, Quantity: 2, Cost: currency.NewFromFloat(200)}}},
+			},
+		},
+		{
+			name: "Should add bonus shares as a new lot",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "us"},
+				{Symbol: "STOCK1", Type: Bonus, Ratio: 1.1, UnitValue: currency.NewFromFloat(5), Date: day2},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 10, UnitValue: currency.NewFromFloat(12), Date: day3, Portfolio: "us", Lot: day2},
+			},
+			args: args{
+				methods: Methods{"us": SpecificLot},
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     1,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(10),
+				Investment:   currency.NewFromFloat(105),
+				Settled:      currency.NewFromFloat(120),
+				Realized:     currency.NewFromFloat(25),
+			},
+			wantSales: Sales{
+				{Symbol: "STOCK1", Date: day3, Quantity: 10, Amount: currency.NewFromFloat(120), Proceeds: currency.NewFromFloat(120), Cost: currency.NewFromFloat(95),
+					Lots: []Lot{{Date: day2, Quantity: 1, Cost: currency.NewFromFloat(5)}, {Date: day1, Quantity: 9, Cost: currency.NewFromFloat(90)}}},
+			},
+		},
+		{
+			name: "Should move subscribed rights cost into the underlying on conversion",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 100, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "br"},
+				{Symbol: "STOCK12", Type: Right, Quantity: 25, Date: day2, Portfolio: "br"},
+				{Symbol: "STOCK12", Type: Sell, Quantity: 5, UnitValue: currency.NewFromFloat(2), Date: day3, Portfolio: "br"},
+				{Symbol: "STOCK12", Type: Subscription, Quantity: 20, UnitValue: currency.NewFromFloat(10), Date: day3, Portfolio: "br", Target: "STOCK13"},
+				{Symbol: "STOCK13", Type: Conversion, Ratio: 1, Date: day4, Target: "STOCK1"},
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     120,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(1200),
+				Investment:   currency.NewFromFloat(1200),
+			},
+			wantSales: Sales{
+				{Symbol: "STOCK12", Date: day3, Quantity: 5, Amount: currency.NewFromFloat(10), Proceeds: currency.NewFromFloat(10)},
+			},
+		},
+		{
+			name: "Should keep subscription date as the lot date after conversion",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "us"},
+				{Symbol: "STOCK12", Type: Right, Quantity: 10, Date: day1, Portfolio: "us"},
+				{Symbol: "STOCK12", Type: Subscription, Quantity: 10, UnitValue: currency.NewFromFloat(5), Date: day2, Portfolio: "us", Target: "STOCK13"},
+				{Symbol: "STOCK13", Type: Conversion, Ratio: 1, Date: day3, Target: "STOCK1"},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 15, UnitValue: currency.NewFromFloat(12), Date: day4, Portfolio: "us"},
+			},
+			args: args{
+				methods: Methods{"us": FIFO},
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     5,
+				AveragePrice: currency.NewFromFloat(5),
+				Cost:         currency.NewFromFloat(25),
+				Investment:   currency.NewFromFloat(150),
+				Settled:      currency.NewFromFloat(180),
+				Realized:     currency.NewFromFloat(55),
+			},
+			wantSales: Sales{
+				{Symbol: "STOCK1", Date: day4, Quantity: 15, Amount: currency.NewFromFloat(180), Proceeds: currency.NewFromFloat(180), Cost: currency.NewFromFloat(125),
+					Lots: []Lot{{Date: day1, Quantity: 10, Cost: currency.NewFromFloat(100)}, {Date: day2, Quantity: 5, Cost: currency.NewFromFloat(25)}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := tt.l.Ledger(tt.args.methods)
+			if got := ledger.Position("STOCK1"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Position() = %v, want %v", got, tt.want)
+			}
+			if got := ledger.Sales(); !reflect.DeepEqual(got, tt.wantSales) {
+				t.Errorf("Sales() = %v, want %v", got, tt.wantSales)
+			}
+		})
+	}
+}
+
+func TestLedger_Lots(t *testing.T) {
+	day1 := time.Date(2022, 4, 25, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2022, 4, 26, 0, 0, 0, 0, time.UTC)
+	day3 := time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)
+
+	l := List{
+		{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "crypto"},
+		{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day2, Portfolio: "crypto"},
+		{Symbol: "STOCK1", Type: Sell, Quantity: 4, UnitValue: currency.NewFromFloat(30), Date: day3, Portfolio: "crypto", Lot: day2},
+		{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day2, Portfolio: "br"},
+	}
+
+	tests := []struct {
+		name    string
+		methods Methods
+		want    []Lot
+	}{
+		{
+			name:    "Should list open lots of lot portfolios",
+			methods: Methods{"crypto": SpecificLot},
+			want: []Lot{
+				{Date: day1, Quantity: 10, Cost: currency.NewFromFloat(100)},
+				{Date: day2, Quantity: 6, Cost: currency.NewFromFloat(120)},
+			},
+		},
+		{
+			name: "Should not list lots of average cost portfolios",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.Ledger(tt.methods).Lots("STOCK1"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lots() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLedger_Oversold(t *testing.T) {
+	day1 := time.Date(2022, 4, 25, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2022, 4, 26, 0, 0, 0, 0, time.UTC)
+
+	l := List{
+		{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "br"},
+		{Symbol: "STOCK1", Type: Buy, Quantity: 5, UnitValue: currency.NewFromFloat(12), Date: day2, Portfolio: "br"},
+		{Symbol: "STOCK1", Type: Sell, Quantity: 20, UnitValue: currency.NewFromFloat(20), Date: day2, Portfolio: "br"},
+	}
+
+	ledger := l.Ledger(nil)
+
+	wantOversold := []Oversell{{Symbol: "STOCK1", Date: day2, Portfolio: "br", Quantity: 15, Held: 10}}
+	if got := ledger.Oversold(); !reflect.DeepEqual(got, wantOversold) {
+		t.Errorf("Oversold() = %v, want %v", got, wantOversold)
+	}
+
+	wantSales := Sales{
+		{Symbol: "STOCK1", Date: day2, DayTrade: true, Quantity: 5, Amount: currency.NewFromFloat(100), Proceeds: currency.NewFromFloat(100), Cost: currency.NewFromFloat(60)},
+		{Symbol: "STOCK1", Date: day2, Quantity: 15, Amount: currency.NewFromFloat(300), Proceeds: currency.NewFromFloat(300), Cost: currency.NewFromFloat(100)},
+	}
+	if got := ledger.Sales(); !reflect.DeepEqual(got, wantSales) {
+		t.Errorf("Sales() = %v, want %v", got, wantSales)
+	}
+}
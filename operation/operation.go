@@ -5,19 +5,44 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"stocks/csv"
 	"stocks/currency"
 	"stocks/separator"
 	"stocks/stock"
 	"strconv"
+	"strings"
 	"time"
 )
 
 const (
 	Buy Type = iota
 	Sell
+	Split
+	ReverseSplit
+	Bonus
+	Right
+	Subscription
+	Conversion
 
-	bitSize    = 64
-	dateLayout = "2006-01-02"
+	bitSize        = 64
+	dateLayout     = "2006-01-02"
+	ratioTolerance = 1e-9
+)
+
+var (
+	columns = []string{"Symbol", "Type", "Qtd", "Unit. Value", "Date", "Brokerage", "Emoluments", "Settlement",
+		"ISS", "IRRF", "Ratio", "Portfolio", "Note", "Lot", "Target"}
+
+	types = map[string]Type{
+		"BUY":           Buy,
+		"SELL":          Sell,
+		"SPLIT":         Split,
+		"REVERSE_SPLIT": ReverseSplit,
+		"BONUS":         Bonus,
+		"RIGHT":         Right,
+		"SUBSCRIPTION":  Subscription,
+		"CONVERSION":    Conversion,
+	}
 )
 
 type (
@@ -25,18 +50,49 @@ type (
 
 	Repository interface {
 		Create(ctx context.Context, operation Operation) error
+		CreateAll(ctx context.Context, operations List) error
 		List(ctx context.Context) (List, error)
+		Get(ctx context.Context, id uint) (Operation, error)
+		Update(ctx context.Context, operation Operation) error
+		Delete(ctx context.Context, id uint) error
+		Changes(ctx context.Context) (Changes, error)
+	}
+
+	Fees struct {
+		Brokerage  currency.Currency
+		Emoluments currency.Currency
+		Settlement currency.Currency
+		ISS        currency.Currency
 	}
 
 	Operation struct {
-		Symbol    stock.Symbol
-		Type      Type
-		Quantity  int
-		UnitValue float64
-		Date      time.Time
+		ID          uint
+		Symbol      stock.Symbol
+		Type        Type
+		Quantity    int
+		UnitValue   currency.Currency
+		Fees        Fees
+		WithheldTax currency.Currency
+		Ratio       float64
+		Date        time.Time
+		Portfolio   string
+		Note        string
+		Lot         time.Time
+		Target      stock.Symbol
 	}
 
 	List []Operation
+
+	Position struct {
+		Symbol       stock.Symbol
+		Quantity     int
+		AveragePrice currency.Currency
+		Cost         currency.Currency
+		Investment   currency.Currency
+		Settled      currency.Currency
+		Realized     currency.Currency
+		DayTrade     currency.Currency
+	}
 )
 
 func (t Type) String() string {
@@ -45,19 +101,107 @@ func (t Type) String() string {
 		return "BUY"
 	case Sell:
 		return "SELL"
+	case Split:
+		return "SPLIT"
+	case ReverseSplit:
+		return "REVERSE_SPLIT"
+	case Bonus:
+		return "BONUS"
+	case Right:
+		return "RIGHT"
+	case Subscription:
+		return "SUBSCRIPTION"
+	case Conversion:
+		return "CONVERSION"
 	default:
 		return ""
 	}
 }
 
+func (t Type) IsEvent() bool {
+	return t == Split || t == ReverseSplit || t == Bonus || t == Conversion
+}
+
+func ParseRatio(raw string) (float64, error) {
+	if from, to, found := strings.Cut(raw, ":"); found {
+		numerator, err := strconv.ParseFloat(to, bitSize)
+		if err != nil {
+			return 0, err
+		}
+
+		denominator, err := strconv.ParseFloat(from, bitSize)
+		if err != nil {
+			return 0, err
+		} else if denominator == 0 {
+			return 0, errors.New("invalid ratio")
+		}
+
+		return numerator / denominator, nil
+	}
+
+	return strconv.ParseFloat(raw, bitSize)
+}
+
+func (f Fees) Total() currency.Currency {
+	return currency.Sum(f.Brokerage, f.Emoluments, f.Settlement, f.ISS)
+}
+
+func (o Operation) Amount() currency.Currency {
+	return o.UnitValue.Mul(o.Quantity)
+}
+
+func (o Operation) Total() currency.Currency {
+	switch o.Type {
+	case Sell:
+		return o.Amount().Sub(o.Fees.Total())
+	default:
+		return o.Amount().Add(o.Fees.Total())
+	}
+}
+
+func (o Operation) Destination() stock.Symbol {
+	switch {
+	case o.Target != "":
+		return o.Target
+	case o.Type == Subscription:
+		return o.Symbol.Receipt()
+	case o.Type == Conversion && o.Symbol.IsReceipt():
+		return o.Symbol.Underlying()
+	default:
+		return ""
+	}
+}
+
+func (o Operation) Shares() int {
+	if o.Type != Subscription || o.Ratio <= 0 {
+		return o.Quantity
+	}
+
+	return eventQuantity(o.Quantity, o.Ratio)
+}
+
+func (o Operation) Fingerprint() string {
+	return fmt.Sprintf("%s|%s|%d|%d|%s|%s|%s", o.Symbol, o.Type, o.Quantity, o.UnitValue.Cents(),
+		o.Date.Format(dateLayout), o.Portfolio, o.Note)
+}
+
 func (o Operation) String() string {
-	return fmt.Sprintf("Symbol=%-6s Type=%s Quantity=%d UnitValue=%s Date=%s",
-		o.Symbol, o.Type, o.Quantity, currency.NewFromFloat(o.UnitValue), o.Date.Format("2006-01-02"))
+	var target string
+	if destination := o.Destination(); destination != "" {
+		target = fmt.Sprintf(" Target=%s", destination)
+	}
+
+	if o.Type.IsEvent() {
+		return fmt.Sprintf("Symbol=%-6s Type=%s Ratio=%s UnitValue=%s Date=%s%s",
+			o.Symbol, o.Type, strconv.FormatFloat(o.Ratio, 'f', -1, bitSize), o.UnitValue, o.Date.Format("2006-01-02"), target)
+	}
+
+	return fmt.Sprintf("Symbol=%-6s Type=%s Quantity=%d UnitValue=%s Fees=%s Date=%s Portfolio=%s%s",
+		o.Symbol, o.Type, o.Quantity, o.UnitValue, o.Fees.Total(), o.Date.Format("2006-01-02"), o.Portfolio, target)
 }
 
 func (l List) Print(writer io.Writer, sep separator.Separator) error {
-	title := fmt.Sprintf("Symbol%sType%sQtd%sUnit. Value%sDate\n", sep, sep, sep, sep)
-	if _, err := io.WriteString(writer, title); err != nil {
+	if _, err := io.WriteString(writer, printTitle(sep)); err != nil {
 		return err
 	}
 
@@ -72,40 +216,313 @@ func (l List) Print(writer io.Writer, sep separator.Separator) error {
 	return nil
 }
 
+func (l List) Position(symbol stock.Symbol) Position {
+	return l.Ledger(nil).Position(symbol)
+}
+
+func (l List) Positions() []Position {
+	return l.Ledger(nil).Positions()
+}
+
+func (l List) With(operations ...Operation) List {
+	output := l
+	for _, operation := range operations {
+		output = output.with(operation)
+	}
+
+	return output
+}
+
+func (l List) with(operation Operation) List {
+	output := make(List, 0, len(l)+1)
+
+	i := 0
+	for ; i < len(l) && !l[i].Date.After(operation.Date); i++ {
+		output = append(output, l[i])
+	}
+
+	output = append(output, operation)
+	return append(output, l[i:]...)
+}
+
+func (l List) ResolveEvent(event Operation) (Operation, error) {
+	held := l.Until(event.Date.AddDate(0, 0, -1)).Position(event.Symbol).Quantity
+	if held <= 0 {
+		return Operation{}, fmt.Errorf("cannot resolve %s of %s: no position before %s",
+			event.Type, event.Symbol, event.Date.Format(dateLayout))
+	}
+
+	switch event.Type {
+	case ReverseSplit:
+		event.Ratio = float64(event.Quantity) / float64(held)
+	default:
+		event.Ratio = float64(held+event.Quantity) / float64(held)
+	}
+
+	event.Quantity = 0
+	return event, nil
+}
+
+func (l List) In(portfolio string) List {
+	if portfolio == "" {
+		return l
+	}
+
+	var output List
+	for _, operation := range l {
+		if operation.Portfolio == portfolio || operation.Type.IsEvent() {
+			output = append(output, operation)
+		}
+	}
+
+	return output
+}
+
+func (l List) Until(date time.Time) List {
+	var output List
+
+	for _, operation := range l {
+		if !operation.Date.After(date) {
+			output = append(output, operation)
+		}
+	}
+
+	return output
+}
+
 func ParseFromCSV(elements []string) (Operation, error) {
 	if len(elements) < 5 {
-		return Operation{}, errors.New("invalid length")
+		return Operation{}, fmt.Errorf("expected at least 5 columns, got %d", len(elements))
 	}
 
-	types := map[string]Type{
-		"BUY":  Buy,
-		"SELL": Sell,
+	t, ok := types[elements[1]]
+	if !ok {
+		return Operation{}, csv.NewFieldError(columns[1], elements[1], errors.New("unknown operation type"))
 	}
 
 	quantity, err := strconv.Atoi(elements[2])
 	if err != nil {
-		return Operation{}, err
+		return Operation{}, csv.NewFieldError(columns[2], elements[2], err)
+	} else if !t.IsEvent() && quantity <= 0 {
+		return Operation{}, csv.NewFieldError(columns[2], elements[2], errors.New("must be positive"))
 	}
 
-	unitValue, err := strconv.ParseFloat(elements[3], bitSize)
+	unitValue, err := currency.Parse(elements[3])
 	if err != nil {
-		return Operation{}, err
+		return Operation{}, csv.NewFieldError(columns[3], elements[3], err)
+	} else if unitValue.IsNegative() {
+		return Operation{}, csv.NewFieldError(columns[3], elements[3], errors.New("must not be negative"))
 	}
 
 	date, err := time.Parse(dateLayout, elements[4])
 	if err != nil {
+		return Operation{}, csv.NewFieldError(columns[4], elements[4], errors.New("expected YYYY-MM-DD"))
+	}
+
+	var target stock.Symbol
+	if len(elements) > 14 {
+		target = stock.Symbol(elements[14])
+		elements = elements[:14]
+	}
+
+	var lot time.Time
+	if len(elements) > 13 {
+		if lot, err = parseLot(elements[13]); err != nil {
+			return Operation{}, csv.NewFieldError(columns[13], elements[13], errors.New("expected YYYY-MM-DD"))
+		}
+
+		elements = elements[:13]
+	}
+
+	var note string
+	if len(elements) > 12 {
+		note = elements[12]
+		elements = elements[:12]
+	}
+
+	var portfolio string
+	if len(elements) > 11 {
+		portfolio = elements[11]
+		elements = elements[:11]
+	}
+
+	var ratio float64
+	if len(elements) > 10 {
+		if elements[10] != "" {
+			if ratio, err = ParseRatio(elements[10]); err != nil {
+				return Operation{}, csv.NewFieldError(columns[10], elements[10], err)
+			}
+		}
+
+		elements = elements[:10]
+	}
+
+	if t.IsEvent() && ratio <= 0 {
+		return Operation{}, csv.NewFieldError(columns[10], strconv.FormatFloat(ratio, 'f', -1, bitSize),
+			errors.New("must be positive for corporate events"))
+	}
+
+	var withheldTax currency.Currency
+	if len(elements) > 9 {
+		if elements[9] != "" {
+			if withheldTax, err = currency.Parse(elements[9]); err != nil {
+				return Operation{}, csv.NewFieldError(columns[9], elements[9], err)
+			}
+		}
+
+		elements = elements[:9]
+	}
+
+	fees, err := ParseFees(elements[5:]...)
+	if err != nil {
+		return Operation{}, err
+	}
+
+	op := Operation{
+		Symbol:      stock.Symbol(elements[0]),
+		Type:        t,
+		Quantity:    quantity,
+		UnitValue:   unitValue,
+		Fees:        fees,
+		WithheldTax: withheldTax,
+		Ratio:       ratio,
+		Date:        date,
+		Portfolio:   portfolio,
+		Note:        note,
+		Lot:         lot,
+		Target:      target,
+	}
+
+	if (t == Subscription || t == Conversion) && op.Destination() == "" {
+		return Operation{}, csv.NewFieldError(columns[14], string(target), fmt.Errorf("required for %s of %s", t, op.Symbol))
+	}
+
+	return op, nil
+}
+
+func (o Operation) Patch(fields map[string]string) (Operation, error) {
+	for field, value := range fields {
+		var err error
+
+		switch field {
+		case "symbol":
+			o.Symbol = stock.Symbol(value)
+		case "type":
+			t, ok := types[value]
+			if !ok {
+				return Operation{}, fmt.Errorf("invalid type: %s", value)
+			}
+			o.Type = t
+		case "quantity":
+			o.Quantity, err = strconv.Atoi(value)
+		case "value":
+			o.UnitValue, err = currency.Parse(value)
+		case "date":
+			o.Date, err = time.Parse(dateLayout, value)
+		case "brokerage":
+			o.Fees.Brokerage, err = currency.Parse(value)
+		case "emoluments":
+			o.Fees.Emoluments, err = currency.Parse(value)
+		case "settlement":
+			o.Fees.Settlement, err = currency.Parse(value)
+		case "iss":
+			o.Fees.ISS, err = currency.Parse(value)
+		case "irrf":
+			o.WithheldTax, err = currency.Parse(value)
+		case "ratio":
+			o.Ratio, err = ParseRatio(value)
+		case "portfolio":
+			o.Portfolio = value
+		case "note":
+			o.Note = value
+		case "lot":
+			o.Lot, err = parseLot(value)
+		case "target":
+			o.Target = stock.Symbol(value)
+		default:
+			return Operation{}, fmt.Errorf("unknown field: %s", field)
+		}
+
+		if err != nil {
+			return Operation{}, fmt.Errorf("invalid %s: %w", field, err)
+		}
+	}
+
+	if err := o.Validate(); err != nil {
 		return Operation{}, err
 	}
 
-	return Operation{
-		Symbol:    stock.Symbol(elements[0]),
-		Type:      types[elements[1]],
-		Quantity:  quantity,
-		UnitValue: unitValue,
-		Date:      date,
+	return o, nil
+}
+
+func (o Operation) Validate() error {
+	switch {
+	case !o.Type.IsEvent() && o.Quantity <= 0:
+		return fmt.Errorf("invalid quantity: %d: must be positive", o.Quantity)
+	case o.UnitValue.IsNegative():
+		return fmt.Errorf("invalid value: %s: must not be negative", o.UnitValue)
+	case o.Type.IsEvent() && o.Ratio <= 0:
+		return fmt.Errorf("invalid ratio: %v: must be positive for corporate events", o.Ratio)
+	case (o.Type == Subscription || o.Type == Conversion) && o.Destination() == "":
+		return fmt.Errorf("invalid target: required for %s of %s", o.Type, o.Symbol)
+	default:
+		return nil
+	}
+}
+
+func ParseFees(elements ...string) (Fees, error) {
+	if len(elements) > 4 {
+		return Fees{}, errors.New("invalid fees length")
+	}
+
+	values := make([]currency.Currency, 4)
+	for i, element := range elements {
+		if element == "" {
+			continue
+		}
+
+		value, err := currency.Parse(element)
+		if err != nil {
+			return Fees{}, csv.NewFieldError(columns[5+i], element, err)
+		}
+
+		values[i] = value
+	}
+
+	return Fees{
+		Brokerage:  values[0],
+		Emoluments: values[1],
+		Settlement: values[2],
+		ISS:        values[3],
 	}, nil
 }
 
+func parseLot(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(dateLayout, raw)
+}
+
+func formatLot(lot time.Time) string {
+	if lot.IsZero() {
+		return ""
+	}
+
+	return lot.Format(dateLayout)
+}
+
+func printTitle(sep separator.Separator) string {
+	switch sep {
+	case separator.Tab:
+		return fmt.Sprintf("ID%sSymbol%sType%sQtd%sUnit. Value%sDate%sFees%sPortfolio\n", sep, sep, sep, sep, sep, sep, sep)
+	default:
+		return fmt.Sprintf("Symbol%sType%sQtd%sUnit. Value%sDate%sBrokerage%sEmoluments%sSettlement%sISS%sIRRF%sRatio%sPortfolio%sNote%sLot%sTarget\n",
+			sep, sep, sep, sep, sep, sep, sep, sep, sep, sep, sep, sep, sep, sep)
+	}
+}
+
 func printLine(operation Operation, sep separator.Separator) string {
 	switch sep {
 	case separator.Tab:
@@ -116,13 +533,22 @@ func printLine(operation Operation, sep separator.Separator) string {
 }
 
 func printRaw(operation Operation, sep separator.Separator) string {
-	return fmt.Sprintf("%s%s%s%s%d%s%.2f%s%s\n",
-		operation.Symbol, sep, operation.Type, sep, operation.Quantity, sep, operation.UnitValue, sep,
-		operation.Date.Format("2006-01-02"))
+	return fmt.Sprintf("%s%s%s%s%d%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s%s\n",
+		operation.Symbol, sep, operation.Type, sep, operation.Quantity, sep, operation.UnitValue.Decimal(), sep,
+		operation.Date.Format("2006-01-02"), sep, operation.Fees.Brokerage.Decimal(), sep,
+		operation.Fees.Emoluments.Decimal(), sep, operation.Fees.Settlement.Decimal(), sep,
+		operation.Fees.ISS.Decimal(), sep, operation.WithheldTax.Decimal(), sep,
+		strconv.FormatFloat(operation.Ratio, 'f', -1, bitSize), sep, operation.Portfolio, sep, operation.Note, sep,
+		formatLot(operation.Lot), sep, operation.Target)
 }
 
 func printBeauty(operation Operation, sep separator.Separator) string {
-	return fmt.Sprintf("%s%s%s%s%d%s%s%s%s\n",
-		operation.Symbol, sep, operation.Type, sep, operation.Quantity, sep, currency.NewFromFloat(operation.UnitValue),
-		sep, operation.Date.Format("2006-01-02"))
+	quantity := strconv.Itoa(operation.Quantity)
+	if operation.Type.IsEvent() {
+		quantity = fmt.Sprintf("x%s", strconv.FormatFloat(operation.Ratio, 'f', -1, bitSize))
+	}
+
+	return fmt.Sprintf("%d%s%s%s%s%s%s%s%s%s%s%s%s%s%s\n",
+		operation.ID, sep, operation.Symbol, sep, operation.Type, sep, quantity, sep, operation.UnitValue,
+		sep, operation.Date.Format("2006-01-02"), sep, operation.Fees.Total(), sep, operation.Portfolio)
 }
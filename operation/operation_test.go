@@ -0,0 +1,660 @@
+package operation
+
+import (
+	"reflect"
+	"stocks/currency"
+	"stocks/stock"
+	"testing"
+	"time"
+)
+
+func TestList_Position(t *testing.T) {
+	day1 := time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC)
+	day3 := time.Date(2022, 4, 29, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		symbol stock.Symbol
+	}
+	tests := []struct {
+		name string
+		l    List
+		args args
+		want Position
+	}{
+		{
+			name: "Should calculate average price for buy operations",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10)},
+				{Symbol: "STOCK2", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(50)},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 30, UnitValue: currency.NewFromFloat(20)},
+			},
+			args: args{
+				symbol: "STOCK1",
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     40,
+				AveragePrice: currency.NewFromFloat(17.5),
+				Cost:         currency.NewFromFloat(700),
+				Investment:   currency.NewFromFloat(700),
+			},
+		},
+		{
+			name: "Should include fees in average price",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Fees: Fees{Brokerage: currency.NewFromFloat(5), Emoluments: currency.NewFromFloat(3), Settlement: currency.NewFromFloat(2)}},
+			},
+			args: args{
+				symbol: "STOCK1",
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     10,
+				AveragePrice: currency.NewFromFloat(11),
+				Cost:         currency.NewFromFloat(110),
+				Investment:   currency.NewFromFloat(110),
+			},
+		},
+		{
+			name: "Should keep average price unchanged on sell operations",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day2},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 15, UnitValue: currency.NewFromFloat(30), Date: day3},
+			},
+			args: args{
+				symbol: "STOCK1",
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     5,
+				AveragePrice: currency.NewFromFloat(15),
+				Cost:         currency.NewFromFloat(75),
+				Investment:   currency.NewFromFloat(300),
+				Settled:      currency.NewFromFloat(450),
+				Realized:     currency.NewFromFloat(225),
+			},
+		},
+		{
+			name: "Should reset average price when position is closed",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 10, UnitValue: currency.NewFromFloat(30), Date: day2},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day3},
+			},
+			args: args{
+				symbol: "STOCK1",
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     10,
+				AveragePrice: currency.NewFromFloat(20),
+				Cost:         currency.NewFromFloat(200),
+				Investment:   currency.NewFromFloat(300),
+				Settled:      currency.NewFromFloat(300),
+				Realized:     currency.NewFromFloat(200),
+			},
+		},
+		{
+			name: "Should keep day trade operations out of average price",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(30), Date: day2},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 10, UnitValue: currency.NewFromFloat(35), Date: day2},
+			},
+			args: args{
+				symbol: "STOCK1",
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     10,
+				AveragePrice: currency.NewFromFloat(10),
+				Cost:         currency.NewFromFloat(100),
+				Investment:   currency.NewFromFloat(100),
+				DayTrade:     currency.NewFromFloat(50),
+			},
+		},
+		{
+			name: "Should apply splits and reverse splits in date order",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(40), Date: day1},
+				{Symbol: "STOCK1", Type: Split, Ratio: 4, Date: day2},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 20, UnitValue: currency.NewFromFloat(10), Date: day2},
+				{Symbol: "STOCK1", Type: ReverseSplit, Ratio: 0.1, Date: day3},
+			},
+			args: args{
+				symbol: "STOCK1",
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     6,
+				AveragePrice: currency.NewFromFloat(100),
+				Cost:         currency.NewFromFloat(600),
+				Investment:   currency.NewFromFloat(600),
+			},
+		},
+		{
+			name: "Should clear the cost when a reverse split leaves no shares",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 5, UnitValue: currency.NewFromFloat(10), Date: day1},
+				{Symbol: "STOCK1", Type: ReverseSplit, Ratio: 0.1, Date: day2},
+			},
+			args: args{
+				symbol: "STOCK1",
+			},
+			want: Position{
+				Symbol:     "STOCK1",
+				Investment: currency.NewFromFloat(50),
+			},
+		},
+		{
+			name: "Should add bonus shares using unit cost",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 105, UnitValue: currency.NewFromFloat(10), Date: day1},
+				{Symbol: "STOCK1", Type: Bonus, Ratio: 1.1, UnitValue: currency.NewFromFloat(5), Date: day2},
+			},
+			args: args{
+				symbol: "STOCK1",
+			},
+			want: Position{
+				Symbol:       "STOCK1",
+				Quantity:     115,
+				AveragePrice: currency.NewFromFloat(9.57),
+				Cost:         currency.NewFromFloat(1100),
+				Investment:   currency.NewFromFloat(1100),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.Position(tt.args.symbol); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Position() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRatio(t *testing.T) {
+	type args struct {
+		raw string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "Should parse decimal ratio",
+			args: args{
+				raw: "1.1",
+			},
+			want: 1.1,
+		},
+		{
+			name: "Should parse proportion ratio",
+			args: args{
+				raw: "10:1",
+			},
+			want: 0.1,
+		},
+		{
+			name: "Should return error if proportion is invalid",
+			args: args{
+				raw: "0:1",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRatio(tt.args.raw)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRatio() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseRatio() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestList_With(t *testing.T) {
+	day1 := time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		operation Operation
+	}
+	tests := []struct {
+		name string
+		l    List
+		args args
+		want List
+	}{
+		{
+			name: "Should insert operation after operations of the same date",
+			l: List{
+				{Symbol: "STOCK1", Date: day1},
+				{Symbol: "STOCK2", Date: day2},
+			},
+			args: args{
+				operation: Operation{Symbol: "STOCK3", Date: day1},
+			},
+			want: List{
+				{Symbol: "STOCK1", Date: day1},
+				{Symbol: "STOCK3", Date: day1},
+				{Symbol: "STOCK2", Date: day2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.With(tt.args.operation); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("With() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestList_Until(t *testing.T) {
+	type args struct {
+		date time.Time
+	}
+	tests := []struct {
+		name string
+		l    List
+		args args
+		want List
+	}{
+		{
+			name: "Should keep operations up to the date inclusive",
+			l: List{
+				{Symbol: "STOCK1", Date: time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)},
+				{Symbol: "STOCK2", Date: time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC)},
+				{Symbol: "STOCK3", Date: time.Date(2022, 4, 29, 0, 0, 0, 0, time.UTC)},
+			},
+			args: args{
+				date: time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+			want: List{
+				{Symbol: "STOCK1", Date: time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)},
+				{Symbol: "STOCK2", Date: time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.Until(tt.args.date); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Until() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestList_ResolveEvent(t *testing.T) {
+	day1 := time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC)
+	l := List{
+		{Symbol: "STOCK1", Type: Buy, Quantity: 30, UnitValue: currency.NewFromFloat(10), Date: day1},
+		{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day2},
+	}
+
+	type args struct {
+		event Operation
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Operation
+		wantErr bool
+	}{
+		{
+			name: "Should derive split ratio from credited shares",
+			args: args{
+				event: Operation{Symbol: "STOCK1", Type: Split, Quantity: 60, Date: day2},
+			},
+			want: Operation{Symbol: "STOCK1", Type: Split, Ratio: 3, Date: day2},
+		},
+		{
+			name: "Should derive reverse split ratio from resulting shares",
+			args: args{
+				event: Operation{Symbol: "STOCK1", Type: ReverseSplit, Quantity: 3, Date: day2},
+			},
+			want: Operation{Symbol: "STOCK1", Type: ReverseSplit, Ratio: 0.1, Date: day2},
+		},
+		{
+			name: "Should return error without a previous position",
+			args: args{
+				event: Operation{Symbol: "STOCK1", Type: Bonus, Quantity: 3, Date: day1},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l.ResolveEvent(tt.args.event)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResolveEvent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveEvent() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestList_In(t *testing.T) {
+	day := time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		portfolio string
+	}
+	tests := []struct {
+		name string
+		l    List
+		args args
+		want List
+	}{
+		{
+			name: "Should keep operations of the portfolio and corporate events",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Date: day, Portfolio: "xp"},
+				{Symbol: "STOCK1", Type: Buy, Date: day, Portfolio: "rico"},
+				{Symbol: "STOCK1", Type: Split, Ratio: 2, Date: day},
+			},
+			args: args{
+				portfolio: "xp",
+			},
+			want: List{
+				{Symbol: "STOCK1", Type: Buy, Date: day, Portfolio: "xp"},
+				{Symbol: "STOCK1", Type: Split, Ratio: 2, Date: day},
+			},
+		},
+		{
+			name: "Should keep every operation for the consolidated view",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Date: day, Portfolio: "xp"},
+				{Symbol: "STOCK1", Type: Buy, Date: day, Portfolio: "rico"},
+			},
+			args: args{
+				portfolio: "",
+			},
+			want: List{
+				{Symbol: "STOCK1", Type: Buy, Date: day, Portfolio: "xp"},
+				{Symbol: "STOCK1", Type: Buy, Date: day, Portfolio: "rico"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.In(tt.args.portfolio); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("In() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperation_Patch(t *testing.T) {
+	op := Operation{
+		ID:        7,
+		Symbol:    "STOCK1",
+		Type:      Buy,
+		Quantity:  10,
+		UnitValue: currency.NewFromFloat(1.23),
+		Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+		Portfolio: "xp",
+	}
+
+	type args struct {
+		fields map[string]string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Operation
+		wantErr bool
+	}{
+		{
+			name: "Should replace informed fields only",
+			args: args{
+				fields: map[string]string{"quantity": "20", "value": "3,21", "brokerage": "4.90", "portfolio": "rico"},
+			},
+			want: Operation{
+				ID:        7,
+				Symbol:    "STOCK1",
+				Type:      Buy,
+				Quantity:  20,
+				UnitValue: currency.NewFromFloat(3.21),
+				Fees:      Fees{Brokerage: currency.NewFromFloat(4.90)},
+				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+				Portfolio: "rico",
+			},
+		},
+		{
+			name: "Should return error for unknown fields",
+			args: args{
+				fields: map[string]string{"price": "1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error for invalid types",
+			args: args{
+				fields: map[string]string{"type": "RENT"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error for invalid values",
+			args: args{
+				fields: map[string]string{"date": "28/04/2022"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error for non positive quantities",
+			args: args{
+				fields: map[string]string{"quantity": "0"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error for negative values",
+			args: args{
+				fields: map[string]string{"value": "-1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error for corporate events without ratio",
+			args: args{
+				fields: map[string]string{"type": "SPLIT"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := op.Patch(tt.args.fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Patch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Patch() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperation_Total(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation Operation
+		want      currency.Currency
+	}{
+		{
+			name:      "Should add fees to buy operations cost",
+			operation: Operation{Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Fees: Fees{Brokerage: currency.NewFromFloat(4), ISS: currency.NewFromFloat(1)}},
+			want:      currency.NewFromFloat(105),
+		},
+		{
+			name:      "Should subtract fees from sell operations proceeds",
+			operation: Operation{Type: Sell, Quantity: 10, UnitValue: currency.NewFromFloat(10), Fees: Fees{Brokerage: currency.NewFromFloat(4), ISS: currency.NewFromFloat(1)}},
+			want:      currency.NewFromFloat(95),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.operation.Total(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Total() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFromCSV(t *testing.T) {
+	type args struct {
+		elements []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Operation
+		wantErr bool
+	}{
+		{
+			name: "Should parse operation without fees",
+			args: args{
+				elements: []string{"STOCK1", "BUY", "10", "1.23", "2022-04-28"},
+			},
+			want: Operation{
+				Symbol:    "STOCK1",
+				Type:      Buy,
+				Quantity:  10,
+				UnitValue: currency.NewFromFloat(1.23),
+				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Should parse operation with fees",
+			args: args{
+				elements: []string{"STOCK1", "SELL", "10", "1.23", "2022-04-28", "4.90", "0.01", "0.03", "0.25"},
+			},
+			want: Operation{
+				Symbol:    "STOCK1",
+				Type:      Sell,
+				Quantity:  10,
+				UnitValue: currency.NewFromFloat(1.23),
+				Fees: Fees{
+					Brokerage:  currency.NewFromFloat(4.90),
+					Emoluments: currency.NewFromFloat(0.01),
+					Settlement: currency.NewFromFloat(0.03),
+					ISS:        currency.NewFromFloat(0.25),
+				},
+				Date: time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Should parse operation with withheld tax",
+			args: args{
+				elements: []string{"STOCK1", "SELL", "10", "1.23", "2022-04-28", "", "", "", "", "0.01"},
+			},
+			want: Operation{
+				Symbol:      "STOCK1",
+				Type:        Sell,
+				Quantity:    10,
+				UnitValue:   currency.NewFromFloat(1.23),
+				WithheldTax: currency.NewFromFloat(0.01),
+				Date:        time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Should parse operation with portfolio",
+			args: args{
+				elements: []string{"STOCK1", "BUY", "10", "1.23", "2022-04-28", "", "", "", "", "", "", "xp"},
+			},
+			want: Operation{
+				Symbol:    "STOCK1",
+				Type:      Buy,
+				Quantity:  10,
+				UnitValue: currency.NewFromFloat(1.23),
+				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+				Portfolio: "xp",
+			},
+		},
+		{
+			name: "Should parse subscription with target",
+			args: args{
+				elements: []string{"FUND12", "SUBSCRIPTION", "10", "95.50", "2022-04-28", "", "", "", "", "", "", "xp", "", "", "FUND13"},
+			},
+			want: Operation{
+				Symbol:    "FUND12",
+				Type:      Subscription,
+				Quantity:  10,
+				UnitValue: currency.NewFromFloat(95.50),
+				Date:      time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC),
+				Portfolio: "xp",
+				Target:    "FUND13",
+			},
+		},
+		{
+			name: "Should return error if conversion target cannot be resolved",
+			args: args{
+				elements: []string{"STOCK1", "CONVERSION", "0", "0", "2022-04-28", "", "", "", "", "", "1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if fees are invalid",
+			args: args{
+				elements: []string{"STOCK1", "SELL", "10", "1.23", "2022-04-28", "abc"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if is missing elements",
+			args: args{
+				elements: []string{"STOCK1", "SELL", "10"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if type is unknown",
+			args: args{
+				elements: []string{"STOCK1", "BUY ", "10", "1.23", "2022-04-28"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if quantity is not positive",
+			args: args{
+				elements: []string{"STOCK1", "BUY", "0", "1.23", "2022-04-28"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error if event has no ratio",
+			args: args{
+				elements: []string{"STOCK1", "SPLIT", "0", "0", "2022-04-28"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFromCSV(tt.args.elements)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFromCSV() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFromCSV() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
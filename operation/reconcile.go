@@ -0,0 +1,41 @@
+package operation
+
+type (
+	Reconciliation struct {
+		Inserted    List
+		Skipped     List
+		Conflicting List
+	}
+)
+
+func (l List) Reconcile(existing List) Reconciliation {
+	stored := map[string]List{}
+	for _, operation := range existing {
+		key := operation.Fingerprint()
+		stored[key] = append(stored[key], operation)
+	}
+
+	var r Reconciliation
+	for _, operation := range l {
+		key := operation.Fingerprint()
+
+		candidates := stored[key]
+		if len(candidates) == 0 {
+			r.Inserted = append(r.Inserted, operation)
+			continue
+		}
+
+		stored[key] = candidates[1:]
+		if sameDetails(candidates[0], operation) {
+			r.Skipped = append(r.Skipped, operation)
+		} else {
+			r.Conflicting = append(r.Conflicting, operation)
+		}
+	}
+
+	return r
+}
+
+func sameDetails(a, b Operation) bool {
+	return a.Fees == b.Fees && a.WithheldTax == b.WithheldTax && a.Ratio == b.Ratio
+}
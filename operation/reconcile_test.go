@@ -0,0 +1,92 @@
+package operation
+
+import (
+	"reflect"
+	"stocks/currency"
+	"testing"
+	"time"
+)
+
+func TestList_Reconcile(t *testing.T) {
+	day := time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC)
+	buy := Operation{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day, Portfolio: "xp"}
+	withFees := buy
+	withFees.Fees = Fees{Brokerage: currency.NewFromFloat(4.90)}
+	stored := buy
+	stored.ID = 1
+
+	type args struct {
+		existing List
+	}
+	tests := []struct {
+		name string
+		l    List
+		args args
+		want Reconciliation
+	}{
+		{
+			name: "Should insert operations not yet stored",
+			l:    List{buy},
+			args: args{
+				existing: List{},
+			},
+			want: Reconciliation{
+				Inserted: List{buy},
+			},
+		},
+		{
+			name: "Should skip operations already stored",
+			l:    List{buy},
+			args: args{
+				existing: List{stored},
+			},
+			want: Reconciliation{
+				Skipped: List{buy},
+			},
+		},
+		{
+			name: "Should insert repeated operations only once per stored copy",
+			l:    List{buy, buy},
+			args: args{
+				existing: List{stored},
+			},
+			want: Reconciliation{
+				Inserted: List{buy},
+				Skipped:  List{buy},
+			},
+		},
+		{
+			name: "Should flag operations that differ from the stored copy",
+			l:    List{withFees},
+			args: args{
+				existing: List{stored},
+			},
+			want: Reconciliation{
+				Conflicting: List{withFees},
+			},
+		},
+		{
+			name: "Should not match operations from other portfolios or notes",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day, Portfolio: "rico"},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day, Portfolio: "xp", Note: "123"},
+			},
+			args: args{
+				existing: List{stored},
+			},
+			want: Reconciliation{
+				Inserted: List{
+					{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day, Portfolio: "rico"},
+					{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day, Portfolio: "xp", Note: "123"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.Reconcile(tt.args.existing); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reconcile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package operation
+
+import (
+	"fmt"
+	"io"
+	"stocks/currency"
+	"stocks/separator"
+	"stocks/stock"
+	"strings"
+	"time"
+)
+
+type (
+	Sale struct {
+		Symbol      stock.Symbol
+		Date        time.Time
+		DayTrade    bool
+		Quantity    int
+		Amount      currency.Currency
+		Proceeds    currency.Currency
+		Cost        currency.Currency
+		WithheldTax currency.Currency
+		Lots        []Lot
+	}
+
+	Sales []Sale
+
+	session struct {
+		symbol         stock.Symbol
+		date           time.Time
+		portfolio      string
+		events         []Operation
+		rights         []Operation
+		subscriptions  []Operation
+		lots           []time.Time
+		boughtQuantity int
+		boughtCost     currency.Currency
+		soldQuantity   int
+		soldAmount     currency.Currency
+		soldProceeds   currency.Currency
+		withheldTax    currency.Currency
+	}
+)
+
+func (s Sale) Result() currency.Currency {
+	return s.Proceeds.Sub(s.Cost)
+}
+
+func (s Sales) Result() currency.Currency {
+	var result currency.Currency
+
+	for _, sale := range s {
+		result = result.Add(sale.Result())
+	}
+
+	return result
+}
+
+func (s Sales) Of(symbol stock.Symbol) Sales {
+	return s.filter(func(sale Sale) bool {
+		return sale.Symbol == symbol
+	})
+}
+
+func (s Sales) DayTrades() Sales {
+	return s.filter(func(sale Sale) bool {
+		return sale.DayTrade
+	})
+}
+
+func (s Sales) SwingTrades() Sales {
+	return s.filter(func(sale Sale) bool {
+		return !sale.DayTrade
+	})
+}
+
+func (s Sales) Between(from, to time.Time) Sales {
+	return s.filter(func(sale Sale) bool {
+		return !sale.Date.Before(from) && sale.Date.Before(to)
+	})
+}
+
+func (s Sales) Print(writer io.Writer, sep separator.Separator) error {
+	title := fmt.Sprintf("Date%sSymbol%sTrade%sQtd.%sAvg. Cost%sAvg. Sale%sCost%sProceeds%sRealized%sLots\n",
+		sep, sep, sep, sep, sep, sep, sep, sep, sep)
+	if _, err := io.WriteString(writer, title); err != nil {
+		return err
+	}
+
+	for _, sale := range s {
+		trade := "Swing"
+		if sale.DayTrade {
+			trade = "Day Trade"
+		}
+
+		lots := make([]string, len(sale.Lots))
+		for i, lot := range sale.Lots {
+			lots[i] = fmt.Sprintf("%s (%d)", lot.Date.Format(dateLayout), lot.Quantity)
+		}
+
+		line := fmt.Sprintf("%s%s%s%s%s%s%d%s%s%s%s%s%s%s%s%s%s%s%s\n", sale.Date.Format(dateLayout), sep, sale.Symbol, sep, trade, sep,
+			sale.Quantity, sep, sale.Cost.Div(sale.Quantity), sep, sale.Proceeds.Div(sale.Quantity), sep, sale.Cost, sep, sale.Proceeds, sep,
+			sale.Result(), sep, strings.Join(lots, "; "))
+
+		if _, err := io.WriteString(writer, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s Sales) filter(fn func(Sale) bool) Sales {
+	var output Sales
+
+	for _, sale := range s {
+		if fn(sale) {
+			output = append(output, sale)
+		}
+	}
+
+	return output
+}
+
+func (l List) Sales() Sales {
+	return l.Ledger(nil).Sales()
+}
+
+func (l List) sessions() []*session {
+	var sessions []*session
+	index := map[string]*session{}
+
+	for _, operation := range l {
+		day := fmt.Sprintf("%s/%s", operation.Symbol, operation.Date.Format(dateLayout))
+		key := fmt.Sprintf("%s/%s", day, operation.Portfolio)
+		if operation.Type.IsEvent() {
+			key = day
+		}
+
+		s, ok := index[key]
+		if !ok {
+			s = &session{
+				symbol:    operation.Symbol,
+				date:      operation.Date,
+				portfolio: operation.Portfolio,
+			}
+			index[key] = s
+			sessions = append(sessions, s)
+
+			if _, ok := index[day]; !ok {
+				index[day] = s
+			}
+		}
+
+		switch operation.Type {
+		case Split, ReverseSplit, Bonus, Conversion:
+			s.events = append(s.events, operation)
+		case Right:
+			s.rights = append(s.rights, operation)
+		case Subscription:
+			s.subscriptions = append(s.subscriptions, operation)
+		case Buy:
+			s.boughtQuantity += operation.Quantity
+			s.boughtCost = s.boughtCost.Add(operation.Total())
+		case Sell:
+			s.soldQuantity += operation.Quantity
+			s.soldAmount = s.soldAmount.Add(operation.Amount())
+			s.soldProceeds = s.soldProceeds.Add(operation.Total())
+			s.withheldTax = s.withheldTax.Add(operation.WithheldTax)
+			if !operation.Lot.IsZero() {
+				s.lots = append(s.lots, operation.Lot)
+			}
+		}
+	}
+
+	return sessions
+}
+
+func (s session) dayTradeQuantity() int {
+	if s.soldQuantity < s.boughtQuantity {
+		return s.soldQuantity
+	}
+
+	return s.boughtQuantity
+}
+
+func (s session) sale(quantity int) Sale {
+	return Sale{
+		Symbol:      s.symbol,
+		Date:        s.date,
+		DayTrade:    true,
+		Quantity:    quantity,
+		Amount:      s.soldAmount.MulRatio(int64(quantity), int64(s.soldQuantity)),
+		Proceeds:    s.soldProceeds.MulRatio(int64(quantity), int64(s.soldQuantity)),
+		Cost:        s.boughtCost.MulRatio(int64(quantity), int64(s.boughtQuantity)),
+		WithheldTax: s.withheldTax.MulRatio(int64(quantity), int64(s.soldQuantity)),
+	}
+}
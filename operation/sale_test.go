@@ -0,0 +1,113 @@
+package operation
+
+import (
+	"bytes"
+	"reflect"
+	"stocks/currency"
+	"stocks/separator"
+	"testing"
+	"time"
+)
+
+func TestList_Sales(t *testing.T) {
+	day1 := time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2022, 4, 28, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		l    List
+		want Sales
+	}{
+		{
+			name: "Should calculate swing trade sales using average price",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day1},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 5, UnitValue: currency.NewFromFloat(30), Fees: Fees{Brokerage: currency.NewFromFloat(10)}, Date: day2},
+			},
+			want: Sales{
+				{Symbol: "STOCK1", Date: day2, Quantity: 5, Amount: currency.NewFromFloat(150), Proceeds: currency.NewFromFloat(140), Cost: currency.NewFromFloat(75)},
+			},
+		},
+		{
+			name: "Should split same day operations into day trade and swing trade",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 5, UnitValue: currency.NewFromFloat(20), Date: day2},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 10, UnitValue: currency.NewFromFloat(30), WithheldTax: currency.NewFromFloat(10), Date: day2},
+			},
+			want: Sales{
+				{Symbol: "STOCK1", Date: day2, DayTrade: true, Quantity: 5, Amount: currency.NewFromFloat(150), Proceeds: currency.NewFromFloat(150), Cost: currency.NewFromFloat(100), WithheldTax: currency.NewFromFloat(5)},
+				{Symbol: "STOCK1", Date: day2, Quantity: 5, Amount: currency.NewFromFloat(150), Proceeds: currency.NewFromFloat(150), Cost: currency.NewFromFloat(50), WithheldTax: currency.NewFromFloat(5)},
+			},
+		},
+		{
+			name: "Should use same day average price for day trade cost",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(50), Date: day1},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 10, UnitValue: currency.NewFromFloat(60), Date: day1},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day2},
+			},
+			want: Sales{
+				{Symbol: "STOCK1", Date: day1, DayTrade: true, Quantity: 10, Amount: currency.NewFromFloat(600), Proceeds: currency.NewFromFloat(600), Cost: currency.NewFromFloat(300)},
+				{Symbol: "STOCK1", Date: day2, Quantity: 10, Amount: currency.NewFromFloat(200), Proceeds: currency.NewFromFloat(200), Cost: currency.NewFromFloat(300)},
+			},
+		},
+		{
+			name: "Should not match day trades across portfolios",
+			l: List{
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "xp"},
+				{Symbol: "STOCK1", Type: Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day2, Portfolio: "rico"},
+				{Symbol: "STOCK1", Type: Sell, Quantity: 10, UnitValue: currency.NewFromFloat(30), Date: day2, Portfolio: "xp"},
+			},
+			want: Sales{
+				{Symbol: "STOCK1", Date: day2, Quantity: 10, Amount: currency.NewFromFloat(300), Proceeds: currency.NewFromFloat(300), Cost: currency.NewFromFloat(150)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.Sales(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sales() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSales_Print(t *testing.T) {
+	day1 := time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		s          Sales
+		wantWriter string
+		wantErr    bool
+	}{
+		{
+			name: "Should print swing and day trade sales properly",
+			s: Sales{
+				{Symbol: "STOCK1", Date: day1, Quantity: 10, Amount: currency.NewFromFloat(300), Proceeds: currency.NewFromFloat(299), Cost: currency.NewFromFloat(150),
+					Lots: []Lot{{Date: day1.AddDate(0, 0, -2), Quantity: 4, Cost: currency.NewFromFloat(40)}, {Date: day1.AddDate(0, 0, -1), Quantity: 6, Cost: currency.NewFromFloat(110)}}},
+				{Symbol: "STOCK2", Date: day1, DayTrade: true, Quantity: 5, Amount: currency.NewFromFloat(50), Proceeds: currency.NewFromFloat(50), Cost: currency.NewFromFloat(60)},
+			},
+			wantWriter: "Date,Symbol,Trade,Qtd.,Avg. Cost,Avg. Sale,Cost,Proceeds,Realized,Lots\n" +
+				"2022-04-27,STOCK1,Swing,10,R$ 15,00,R$ 29,90,R$ 150,00,R$ 299,00,R$ 149,00,2022-04-25 (4); 2022-04-26 (6)\n" +
+				"2022-04-27,STOCK2,Day Trade,5,R$ 12,00,R$ 10,00,R$ 60,00,R$ 50,00,-(R$ 10,00),\n",
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			err := tt.s.Print(writer, separator.Comma)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Print() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("Print() gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
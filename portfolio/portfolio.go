@@ -0,0 +1,60 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"stocks/operation"
+	"stocks/separator"
+)
+
+const (
+	Default = "default"
+)
+
+type (
+	Repository interface {
+		CreatePortfolio(ctx context.Context, portfolio Portfolio) error
+		Portfolios(ctx context.Context) (Portfolios, error)
+		UpdatePortfolio(ctx context.Context, portfolio Portfolio) error
+	}
+
+	Portfolio struct {
+		Name   string
+		Broker string
+		Method operation.Method
+	}
+
+	Portfolios []Portfolio
+)
+
+func OrDefault(name string) string {
+	if name == "" {
+		return Default
+	}
+
+	return name
+}
+
+func (p Portfolios) Methods() operation.Methods {
+	methods := make(operation.Methods, len(p))
+	for _, portfolio := range p {
+		methods[portfolio.Name] = portfolio.Method
+	}
+
+	return methods
+}
+
+func (p Portfolios) Print(writer io.Writer, sep separator.Separator) error {
+	if _, err := io.WriteString(writer, fmt.Sprintf("Name%sBroker%sMethod\n", sep, sep)); err != nil {
+		return err
+	}
+
+	for _, portfolio := range p {
+		if _, err := io.WriteString(writer, fmt.Sprintf("%s%s%s%s%s\n", portfolio.Name, sep, portfolio.Broker, sep, portfolio.Method)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package stock
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	CachedProvider struct {
+		Provider   Provider
+		Repository InfoRepository
+		TTL        time.Duration
+		Now        func() time.Time
+	}
+)
+
+func NewCachedProvider(provider Provider, repository InfoRepository, ttl time.Duration) *CachedProvider {
+	return &CachedProvider{
+		Provider:   provider,
+		Repository: repository,
+		TTL:        ttl,
+		Now:        time.Now,
+	}
+}
+
+func (p CachedProvider) Details(ctx context.Context, symbol Symbol) (Details, error) {
+	return p.Provider.Details(ctx, symbol)
+}
+
+func (p CachedProvider) History(ctx context.Context, symbol Symbol, from, to time.Time) (Candles, error) {
+	return p.Provider.History(ctx, symbol, from, to)
+}
+
+func (p CachedProvider) LastInfo(ctx context.Context, symbol Symbol) (Info, error) {
+	now := p.Now()
+
+	cached, fetchedAt, cacheErr := p.Repository.GetInfo(ctx, symbol)
+	if cacheErr == nil && now.Sub(fetchedAt) < p.TTL {
+		return cached, nil
+	}
+
+	info, err := p.Provider.LastInfo(ctx, symbol)
+	if err != nil {
+		if cacheErr != nil {
+			return Info{}, err
+		}
+
+		cached.Stale = true
+		return cached, nil
+	}
+
+	if err := p.Repository.InsertInfo(ctx, info, now); err != nil {
+		return Info{}, err
+	}
+
+	return info, nil
+}
+
+func (p CachedProvider) LastInfos(ctx context.Context, symbols []Symbol) (map[Symbol]Info, error) {
+	now := p.Now()
+	infos := make(map[Symbol]Info, len(symbols))
+	cached := map[Symbol]Info{}
+
+	var expired []Symbol
+	for _, symbol := range unique(symbols) {
+		info, fetchedAt, err := p.Repository.GetInfo(ctx, symbol)
+		if err == nil {
+			if now.Sub(fetchedAt) < p.TTL {
+				infos[symbol] = info
+				continue
+			}
+
+			cached[symbol] = info
+		}
+
+		expired = append(expired, symbol)
+	}
+
+	if len(expired) == 0 {
+		return infos, nil
+	}
+
+	fresh, err := p.Provider.LastInfos(ctx, expired)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	failed := Failed(err, expired)
+	missing := QuoteErrors{}
+
+	for _, symbol := range expired {
+		if info, ok := fresh[symbol]; ok {
+			if err := p.Repository.InsertInfo(ctx, info, now); err != nil {
+				return nil, err
+			}
+
+			infos[symbol] = info
+		} else if info, ok := cached[symbol]; ok {
+			info.Stale = true
+			infos[symbol] = info
+		} else if err, ok := failed[symbol]; ok {
+			missing[symbol] = err
+		}
+	}
+
+	switch {
+	case len(missing) > 0 && len(infos) == 0:
+		return nil, missing
+	case len(missing) > 0:
+		return infos, missing
+	}
+
+	return infos, nil
+}
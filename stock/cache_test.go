@@ -0,0 +1,166 @@
+package stock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"stocks/currency"
+	"testing"
+	"time"
+)
+
+type (
+	fakeProvider struct {
+		info Info
+		err  error
+	}
+
+	fakeInfoRepository struct {
+		info      Info
+		fetchedAt time.Time
+		found     bool
+	}
+)
+
+func (p fakeProvider) Details(context.Context, Symbol) (Details, error) {
+	return Details{}, nil
+}
+
+func (p fakeProvider) LastInfo(context.Context, Symbol) (Info, error) {
+	return p.info, p.err
+}
+
+func (p fakeProvider) LastInfos(ctx context.Context, symbols []Symbol) (map[Symbol]Info, error) {
+	return SequentialLastInfos(ctx, p, symbols)
+}
+
+func (p fakeProvider) History(context.Context, Symbol, time.Time, time.Time) (Candles, error) {
+	return nil, nil
+}
+
+func (r *fakeInfoRepository) GetInfo(context.Context, Symbol) (Info, time.Time, error) {
+	if !r.found {
+		return Info{}, time.Time{}, errors.New("not found")
+	}
+
+	return r.info, r.fetchedAt, nil
+}
+
+func (r *fakeInfoRepository) InsertInfo(_ context.Context, info Info, fetchedAt time.Time) error {
+	r.info, r.fetchedAt, r.found = info, fetchedAt, true
+	return nil
+}
+
+func TestCachedProvider_LastInfo(t *testing.T) {
+	now := time.Date(2023, 12, 29, 12, 0, 0, 0, time.UTC)
+	cached := Info{Symbol: "STOCK1", LastPrice: currency.New(1000)}
+	fresh := Info{Symbol: "STOCK1", LastPrice: currency.New(1100)}
+
+	tests := []struct {
+		name       string
+		provider   fakeProvider
+		repository *fakeInfoRepository
+		want       Info
+		wantCached Info
+		wantErr    bool
+	}{
+		{
+			name:       "Should use cached info within ttl",
+			provider:   fakeProvider{info: fresh},
+			repository: &fakeInfoRepository{info: cached, fetchedAt: now.Add(-time.Minute), found: true},
+			want:       cached,
+			wantCached: cached,
+		},
+		{
+			name:       "Should refresh cached info after ttl",
+			provider:   fakeProvider{info: fresh},
+			repository: &fakeInfoRepository{info: cached, fetchedAt: now.Add(-time.Hour), found: true},
+			want:       fresh,
+			wantCached: fresh,
+		},
+		{
+			name:       "Should fall back to stale info when provider fails",
+			provider:   fakeProvider{err: errors.New("offline")},
+			repository: &fakeInfoRepository{info: cached, fetchedAt: now.Add(-time.Hour), found: true},
+			want:       Info{Symbol: "STOCK1", LastPrice: currency.New(1000), Stale: true},
+			wantCached: cached,
+		},
+		{
+			name:       "Should return error when provider fails without cache",
+			provider:   fakeProvider{err: errors.New("offline")},
+			repository: &fakeInfoRepository{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := CachedProvider{
+				Provider:   tt.provider,
+				Repository: tt.repository,
+				TTL:        15 * time.Minute,
+				Now:        func() time.Time { return now },
+			}
+			got, err := p.LastInfo(context.Background(), "STOCK1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LastInfo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LastInfo() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.repository.info, tt.wantCached) {
+				t.Errorf("LastInfo() cached = %v, want %v", tt.repository.info, tt.wantCached)
+			}
+		})
+	}
+}
+
+func TestCachedProvider_LastInfos(t *testing.T) {
+	now := time.Date(2023, 12, 29, 12, 0, 0, 0, time.UTC)
+	cached := Info{Symbol: "STOCK1", LastPrice: currency.New(1000)}
+
+	tests := []struct {
+		name       string
+		provider   fakeProvider
+		repository *fakeInfoRepository
+		want       map[Symbol]Info
+		wantErr    bool
+	}{
+		{
+			name:       "Should not call provider within ttl",
+			provider:   fakeProvider{err: errors.New("offline")},
+			repository: &fakeInfoRepository{info: cached, fetchedAt: now, found: true},
+			want:       map[Symbol]Info{"STOCK1": cached},
+		},
+		{
+			name:       "Should fall back to stale info when provider fails",
+			provider:   fakeProvider{err: errors.New("offline")},
+			repository: &fakeInfoRepository{info: cached, fetchedAt: now.Add(-time.Hour), found: true},
+			want:       map[Symbol]Info{"STOCK1": {Symbol: "STOCK1", LastPrice: currency.New(1000), Stale: true}},
+		},
+		{
+			name:       "Should return error when provider fails without cache",
+			provider:   fakeProvider{err: errors.New("offline")},
+			repository: &fakeInfoRepository{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := CachedProvider{
+				Provider:   tt.provider,
+				Repository: tt.repository,
+				TTL:        15 * time.Minute,
+				Now:        func() time.Time { return now },
+			}
+			got, err := p.LastInfos(context.Background(), []Symbol{"STOCK1"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LastInfos() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LastInfos() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
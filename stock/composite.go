@@ -0,0 +1,204 @@
+package stock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	Source struct {
+		Name     string
+		Provider Provider
+	}
+
+	CompositeProvider struct {
+		Sources   []Source
+		Overrides map[Symbol][]Source
+	}
+)
+
+func NewCompositeProvider(providers map[string]Provider, order []string, overrides map[Symbol][]string) (*CompositeProvider, error) {
+	if len(order) == 0 {
+		return nil, fmt.Errorf("no quote provider configured")
+	}
+
+	sources, err := resolve(providers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[Symbol][]Source, len(overrides))
+	for symbol, names := range overrides {
+		if resolved[symbol], err = resolve(providers, names); err != nil {
+			return nil, fmt.Errorf("%w for %s", err, symbol)
+		}
+	}
+
+	return &CompositeProvider{
+		Sources:   sources,
+		Overrides: resolved,
+	}, nil
+}
+
+func (p CompositeProvider) Details(ctx context.Context, symbol Symbol) (Details, error) {
+	var details Details
+
+	_, err := p.try(ctx, symbol, func(provider Provider) (err error) {
+		details, err = provider.Details(ctx, symbol)
+		return err
+	})
+
+	return details, err
+}
+
+func (p CompositeProvider) LastInfo(ctx context.Context, symbol Symbol) (Info, error) {
+	var info Info
+
+	source, err := p.try(ctx, symbol, func(provider Provider) (err error) {
+		info, err = provider.LastInfo(ctx, symbol)
+		return err
+	})
+	if err != nil {
+		return Info{}, err
+	}
+
+	if info.Source == "" {
+		info.Source = source
+	}
+
+	return info, nil
+}
+
+func (p CompositeProvider) LastInfos(ctx context.Context, symbols []Symbol) (map[Symbol]Info, error) {
+	var keys []string
+	groups := map[string][]Symbol{}
+	chains := map[string][]Source{}
+
+	for _, symbol := range unique(symbols) {
+		sources := p.sources(symbol)
+
+		names := make([]string, len(sources))
+		for i, source := range sources {
+			names[i] = source.Name
+		}
+
+		key := strings.Join(names, ",")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+			chains[key] = sources
+		}
+
+		groups[key] = append(groups[key], symbol)
+	}
+
+	infos := make(map[Symbol]Info, len(symbols))
+	failures := map[Symbol][]string{}
+
+	for _, key := range keys {
+		pending := groups[key]
+
+		for _, source := range chains[key] {
+			if len(pending) == 0 {
+				break
+			}
+
+			result, err := source.Provider.LastInfos(ctx, pending)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			failed := Failed(err, pending)
+
+			var missing []Symbol
+			for _, symbol := range pending {
+				info, ok := result[symbol]
+				if !ok {
+					if err, ok := failed[symbol]; ok {
+						failures[symbol] = append(failures[symbol], fmt.Sprintf("%s: %v", source.Name, err))
+					}
+
+					missing = append(missing, symbol)
+					continue
+				}
+
+				if info.Source == "" {
+					info.Source = source.Name
+				}
+
+				infos[symbol] = info
+			}
+
+			pending = missing
+		}
+	}
+
+	failed := QuoteErrors{}
+	for symbol, messages := range failures {
+		if _, ok := infos[symbol]; !ok {
+			failed[symbol] = fmt.Errorf("no quote provider answered for %s (%s)", symbol, strings.Join(messages, "; "))
+		}
+	}
+
+	switch {
+	case len(failed) > 0 && len(infos) == 0:
+		return nil, failed
+	case len(failed) > 0:
+		return infos, failed
+	}
+
+	return infos, nil
+}
+
+func (p CompositeProvider) History(ctx context.Context, symbol Symbol, from, to time.Time) (Candles, error) {
+	var candles Candles
+
+	_, err := p.try(ctx, symbol, func(provider Provider) (err error) {
+		candles, err = provider.History(ctx, symbol, from, to)
+		return err
+	})
+
+	return candles, err
+}
+
+func (p CompositeProvider) try(ctx context.Context, symbol Symbol, call func(provider Provider) error) (string, error) {
+	var failures []string
+	for _, source := range p.sources(symbol) {
+		err := call(source.Provider)
+		if err == nil {
+			return source.Name, nil
+		}
+
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		failures = append(failures, fmt.Sprintf("%s: %v", source.Name, err))
+	}
+
+	return "", fmt.Errorf("no quote provider answered for %s (%s)", symbol, strings.Join(failures, "; "))
+}
+
+func (p CompositeProvider) sources(symbol Symbol) []Source {
+	if sources, ok := p.Overrides[symbol]; ok {
+		return sources
+	}
+
+	return p.Sources
+}
+
+func resolve(providers map[string]Provider, names []string) ([]Source, error) {
+	sources := make([]Source, len(names))
+
+	for i, name := range names {
+		provider, ok := providers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown quote provider %s", name)
+		}
+
+		sources[i] = Source{Name: name, Provider: provider}
+	}
+
+	return sources, nil
+}
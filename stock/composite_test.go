@@ -0,0 +1,159 @@
+package stock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"stocks/currency"
+	"testing"
+)
+
+func TestCompositeProvider_LastInfo(t *testing.T) {
+	providers := map[string]Provider{
+		"failing":   fakeProvider{err: errors.New("offline")},
+		"primary":   fakeProvider{info: Info{Symbol: "STOCK1", LastPrice: currency.New(1000)}},
+		"secondary": fakeProvider{info: Info{Symbol: "STOCK1", LastPrice: currency.New(1100)}},
+	}
+
+	tests := []struct {
+		name      string
+		order     []string
+		overrides map[Symbol][]string
+		want      Info
+		wantErr   bool
+	}{
+		{
+			name:  "Should use first provider that answers",
+			order: []string{"failing", "primary", "secondary"},
+			want:  Info{Symbol: "STOCK1", LastPrice: currency.New(1000), Source: "primary"},
+		},
+		{
+			name:      "Should use symbol overrides",
+			order:     []string{"failing", "primary", "secondary"},
+			overrides: map[Symbol][]string{"STOCK1": {"secondary"}},
+			want:      Info{Symbol: "STOCK1", LastPrice: currency.New(1100), Source: "secondary"},
+		},
+		{
+			name:    "Should return error if every provider fails",
+			order:   []string{"failing"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewCompositeProvider(providers, tt.order, tt.overrides)
+			if err != nil {
+				t.Fatalf("NewCompositeProvider() error = %v", err)
+			}
+			got, err := p.LastInfo(context.Background(), "STOCK1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LastInfo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LastInfo() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCompositeProvider(t *testing.T) {
+	providers := map[string]Provider{"primary": fakeProvider{}}
+
+	if _, err := NewCompositeProvider(providers, []string{"unknown"}, nil); err == nil {
+		t.Errorf("NewCompositeProvider() expected error for unknown provider")
+	}
+
+	if _, err := NewCompositeProvider(providers, []string{"primary"}, map[Symbol][]string{"STOCK1": {"unknown"}}); err == nil {
+		t.Errorf("NewCompositeProvider() expected error for unknown override")
+	}
+
+	if _, err := NewCompositeProvider(providers, nil, nil); err == nil {
+		t.Errorf("NewCompositeProvider() expected error without sources")
+	}
+}
+
+type batchProvider struct {
+	fakeProvider
+	infos map[Symbol]Info
+	calls *int
+}
+
+func (p batchProvider) Details(_ context.Context, symbol Symbol) (Details, error) {
+	return p.infos[symbol].Details, nil
+}
+
+func (p batchProvider) LastInfos(_ context.Context, symbols []Symbol) (map[Symbol]Info, error) {
+	*p.calls++
+
+	infos := map[Symbol]Info{}
+	for _, symbol := range symbols {
+		if info, ok := p.infos[symbol]; ok {
+			infos[symbol] = info
+		}
+	}
+
+	return infos, nil
+}
+
+func TestCompositeProvider_LastInfos(t *testing.T) {
+	var primaryCalls, secondaryCalls int
+	providers := map[string]Provider{
+		"primary": batchProvider{
+			infos: map[Symbol]Info{"STOCK1": {Symbol: "STOCK1", LastPrice: currency.New(1000)}},
+			calls: &primaryCalls,
+		},
+		"secondary": batchProvider{
+			infos: map[Symbol]Info{
+				"STOCK2": {Symbol: "STOCK2", LastPrice: currency.New(2000)},
+				"STOCK3": {Symbol: "STOCK3", LastPrice: currency.New(3000)},
+			},
+			calls: &secondaryCalls,
+		},
+	}
+
+	p, err := NewCompositeProvider(providers, []string{"primary", "secondary"}, map[Symbol][]string{"STOCK3": {"secondary"}})
+	if err != nil {
+		t.Fatalf("NewCompositeProvider() error = %v", err)
+	}
+
+	got, err := p.LastInfos(context.Background(), []Symbol{"STOCK1", "STOCK2", "STOCK3", "STOCK4"})
+	if err != nil {
+		t.Fatalf("LastInfos() error = %v", err)
+	}
+
+	want := map[Symbol]Info{
+		"STOCK1": {Symbol: "STOCK1", LastPrice: currency.New(1000), Source: "primary"},
+		"STOCK2": {Symbol: "STOCK2", LastPrice: currency.New(2000), Source: "secondary"},
+		"STOCK3": {Symbol: "STOCK3", LastPrice: currency.New(3000), Source: "secondary"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LastInfos() got = %v, want %v", got, want)
+	}
+
+	if primaryCalls != 1 || secondaryCalls != 2 {
+		t.Errorf("LastInfos() calls = %d, %d, want 1, 2", primaryCalls, secondaryCalls)
+	}
+}
+
+func TestCompositeProvider_LastInfos_Failures(t *testing.T) {
+	providers := map[string]Provider{
+		"primary":   fakeProvider{err: errors.New("offline")},
+		"secondary": fakeProvider{err: errors.New("not found")},
+	}
+
+	p, err := NewCompositeProvider(providers, []string{"primary", "secondary"}, nil)
+	if err != nil {
+		t.Fatalf("NewCompositeProvider() error = %v", err)
+	}
+
+	got, err := p.LastInfos(context.Background(), []Symbol{"STOCK1"})
+	if got != nil {
+		t.Errorf("LastInfos() got = %v, want nil", got)
+	}
+
+	want := "no quote provider answered for STOCK1 (primary: offline; secondary: not found)"
+	if failed := Failed(err, nil); failed["STOCK1"] == nil || failed["STOCK1"].Error() != want {
+		t.Errorf("LastInfos() error = %v, want %v", err, want)
+	}
+}
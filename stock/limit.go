@@ -0,0 +1,170 @@
+package stock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type (
+	Limits struct {
+		MaxInFlight int
+		PerSecond   float64
+		Retries     int
+		Backoff     time.Duration
+	}
+
+	LimitedProvider struct {
+		Provider Provider
+		Limits   Limits
+
+		slots chan struct{}
+		mutex *sync.Mutex
+		next  *time.Time
+	}
+
+	temporary interface {
+		Temporary() bool
+	}
+
+	sequential interface {
+		Sequential() bool
+	}
+)
+
+func NewLimitedProvider(provider Provider, limits Limits) *LimitedProvider {
+	if limits.MaxInFlight < 1 {
+		limits.MaxInFlight = 1
+	}
+
+	return &LimitedProvider{
+		Provider: provider,
+		Limits:   limits,
+		slots:    make(chan struct{}, limits.MaxInFlight),
+		mutex:    &sync.Mutex{},
+		next:     &time.Time{},
+	}
+}
+
+func (p LimitedProvider) Details(ctx context.Context, symbol Symbol) (Details, error) {
+	var details Details
+
+	err := p.do(ctx, func() (err error) {
+		details, err = p.Provider.Details(ctx, symbol)
+		return err
+	})
+
+	return details, err
+}
+
+func (p LimitedProvider) LastInfo(ctx context.Context, symbol Symbol) (Info, error) {
+	var info Info
+
+	err := p.do(ctx, func() (err error) {
+		info, err = p.Provider.LastInfo(ctx, symbol)
+		return err
+	})
+
+	return info, err
+}
+
+func (p LimitedProvider) LastInfos(ctx context.Context, symbols []Symbol) (map[Symbol]Info, error) {
+	if s, ok := p.Provider.(sequential); ok && s.Sequential() {
+		return SequentialLastInfos(ctx, p, symbols)
+	}
+
+	var infos map[Symbol]Info
+
+	err := p.do(ctx, func() (err error) {
+		infos, err = p.Provider.LastInfos(ctx, symbols)
+		return err
+	})
+
+	return infos, err
+}
+
+func (p LimitedProvider) History(ctx context.Context, symbol Symbol, from, to time.Time) (Candles, error) {
+	var candles Candles
+
+	err := p.do(ctx, func() (err error) {
+		candles, err = p.Provider.History(ctx, symbol, from, to)
+		return err
+	})
+
+	return candles, err
+}
+
+func (p LimitedProvider) do(ctx context.Context, call func() error) error {
+	backoff := p.Limits.Backoff
+
+	for attempt := 0; ; attempt++ {
+		err := p.call(ctx, call)
+		if err == nil || attempt >= p.Limits.Retries || !isTemporary(err) {
+			return err
+		}
+
+		if err := sleep(ctx, backoff); err != nil {
+			return err
+		}
+
+		backoff *= 2
+	}
+}
+
+func (p LimitedProvider) call(ctx context.Context, call func() error) error {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	defer func() {
+		<-p.slots
+	}()
+
+	if err := sleep(ctx, p.reserve()); err != nil {
+		return err
+	}
+
+	return call()
+}
+
+func (p LimitedProvider) reserve() time.Duration {
+	if p.Limits.PerSecond <= 0 {
+		return 0
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	slot := *p.next
+	if slot.Before(now) {
+		slot = now
+	}
+
+	*p.next = slot.Add(time.Duration(float64(time.Second) / p.Limits.PerSecond))
+	return slot.Sub(now)
+}
+
+func isTemporary(err error) bool {
+	var t temporary
+	return errors.As(err, &t) && t.Temporary()
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
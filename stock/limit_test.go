@@ -0,0 +1,193 @@
+package stock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	statusError struct {
+		temporary bool
+	}
+
+	flakyProvider struct {
+		fakeProvider
+		failures int
+		err      error
+		delay    time.Duration
+
+		mutex    sync.Mutex
+		calls    int
+		inFlight int
+		peak     int
+	}
+
+	sequentialProvider struct {
+		*flakyProvider
+	}
+)
+
+func (e statusError) Error() string {
+	return "status error"
+}
+
+func (e statusError) Temporary() bool {
+	return e.temporary
+}
+
+func (p *flakyProvider) LastInfo(ctx context.Context, symbol Symbol) (Info, error) {
+	p.mutex.Lock()
+	p.calls++
+	call := p.calls
+	p.inFlight++
+	if p.inFlight > p.peak {
+		p.peak = p.inFlight
+	}
+	p.mutex.Unlock()
+
+	time.Sleep(p.delay)
+
+	p.mutex.Lock()
+	p.inFlight--
+	p.mutex.Unlock()
+
+	if call <= p.failures {
+		return Info{}, p.err
+	}
+
+	return Info{Symbol: symbol}, nil
+}
+
+func (p sequentialProvider) LastInfos(ctx context.Context, symbols []Symbol) (map[Symbol]Info, error) {
+	return SequentialLastInfos(ctx, p.flakyProvider, symbols)
+}
+
+func (p sequentialProvider) Sequential() bool {
+	return true
+}
+
+func TestLimitedProvider_LastInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  *flakyProvider
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "Should retry temporary errors",
+			provider:  &flakyProvider{failures: 2, err: statusError{temporary: true}},
+			wantCalls: 3,
+		},
+		{
+			name:      "Should not retry permanent errors",
+			provider:  &flakyProvider{failures: 2, err: statusError{}},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "Should give up after max retries",
+			provider:  &flakyProvider{failures: 5, err: statusError{temporary: true}},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewLimitedProvider(tt.provider, Limits{MaxInFlight: 1, Retries: 2, Backoff: time.Millisecond})
+			_, err := p.LastInfo(context.Background(), "STOCK1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LastInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.provider.calls != tt.wantCalls {
+				t.Errorf("LastInfo() calls = %v, want %v", tt.provider.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestLimitedProvider_MaxInFlight(t *testing.T) {
+	provider := &flakyProvider{delay: 5 * time.Millisecond}
+	p := NewLimitedProvider(provider, Limits{MaxInFlight: 2})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = p.LastInfo(context.Background(), "STOCK1")
+		}()
+	}
+	wg.Wait()
+
+	if provider.peak > 2 {
+		t.Errorf("LastInfo() peak in flight = %v, want at most 2", provider.peak)
+	}
+}
+
+func TestLimitedProvider_Cancel(t *testing.T) {
+	provider := &flakyProvider{failures: 5, err: statusError{temporary: true}}
+	p := NewLimitedProvider(provider, Limits{MaxInFlight: 1, Retries: 5, Backoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := p.LastInfo(ctx, "STOCK1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("LastInfo() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestLimitedProvider_LastInfos(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   *flakyProvider
+		want       []Symbol
+		wantFailed []Symbol
+		wantCalls  int
+	}{
+		{
+			name:      "Should retry each symbol of sequential providers",
+			provider:  &flakyProvider{failures: 1, err: statusError{temporary: true}},
+			want:      []Symbol{"STOCK1", "STOCK2"},
+			wantCalls: 3,
+		},
+		{
+			name:       "Should keep the error of each failed symbol",
+			provider:   &flakyProvider{failures: 1, err: statusError{}},
+			want:       []Symbol{"STOCK2"},
+			wantFailed: []Symbol{"STOCK1"},
+			wantCalls:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewLimitedProvider(sequentialProvider{tt.provider}, Limits{MaxInFlight: 1, Retries: 2, Backoff: time.Millisecond})
+			got, err := p.LastInfos(context.Background(), []Symbol{"STOCK1", "STOCK2"})
+
+			if len(got) != len(tt.want) {
+				t.Errorf("LastInfos() got = %v, want %v", got, tt.want)
+			}
+			for _, symbol := range tt.want {
+				if _, ok := got[symbol]; !ok {
+					t.Errorf("LastInfos() missing %s", symbol)
+				}
+			}
+
+			failed := Failed(err, nil)
+			if len(failed) != len(tt.wantFailed) {
+				t.Errorf("LastInfos() error = %v, want failures for %v", err, tt.wantFailed)
+			}
+			for _, symbol := range tt.wantFailed {
+				if !errors.Is(failed[symbol], statusError{}) {
+					t.Errorf("LastInfos() error for %s = %v, want %v", symbol, failed[symbol], statusError{})
+				}
+			}
+
+			if tt.provider.calls != tt.wantCalls {
+				t.Errorf("LastInfos() calls = %v, want %v", tt.provider.calls, tt.wantCalls)
+			}
+		})
+	}
+}
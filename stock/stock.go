@@ -2,44 +2,133 @@ package stock
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"stocks/currency"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	Stock Class = iota
+	RealEstateFund
+	Unclassified Class = -1
 )
 
 type (
 	Symbol string
 
+	Class int
+
 	Fetcher struct {
 		Repository Repository
 		Provider   Provider
 	}
 
 	Details struct {
-		Symbol    Symbol
-		Name      string
-		Sector    string
-		SubSector string
-		Segment   string
+		Symbol        Symbol
+		Class         Class
+		Name          string
+		CNPJ          string
+		Sector        string
+		SubSector     string
+		Segment       string
+		LastDividend  currency.Currency
+		DividendYield float64
 	}
 
 	Info struct {
 		Symbol       Symbol
-		OpeningPrice float64
-		MaxPrice     float64
-		MinPrice     float64
-		LastPrice    float64
+		OpeningPrice currency.Currency
+		MaxPrice     currency.Currency
+		MinPrice     currency.Currency
+		LastPrice    currency.Currency
 		Change       float64
+		Source       string
+		Stale        bool
+		Details      Details
 	}
 
+	Candle struct {
+		Symbol Symbol
+		Date   time.Time
+		Open   currency.Currency
+		High   currency.Currency
+		Low    currency.Currency
+		Close  currency.Currency
+		Volume int64
+	}
+
+	Candles []Candle
+
+	QuoteErrors map[Symbol]error
+
 	Repository interface {
 		GetDetails(ctx context.Context, symbol Symbol) (Details, error)
 		InsertDetails(ctx context.Context, details Details) error
+		UpdateDetails(ctx context.Context, details Details) error
+	}
+
+	InfoRepository interface {
+		GetInfo(ctx context.Context, symbol Symbol) (Info, time.Time, error)
+		InsertInfo(ctx context.Context, info Info, fetchedAt time.Time) error
+	}
+
+	CandleRepository interface {
+		InsertCandles(ctx context.Context, candles Candles) error
+		Candles(ctx context.Context, symbol Symbol, from, to time.Time) (Candles, error)
+	}
+
+	InfoProvider interface {
+		LastInfo(ctx context.Context, symbol Symbol) (Info, error)
 	}
 
 	Provider interface {
 		Details(ctx context.Context, symbol Symbol) (Details, error)
 		LastInfo(ctx context.Context, symbol Symbol) (Info, error)
+		LastInfos(ctx context.Context, symbols []Symbol) (map[Symbol]Info, error)
+		History(ctx context.Context, symbol Symbol, from, to time.Time) (Candles, error)
 	}
 )
 
+func (c Class) String() string {
+	switch c {
+	case Stock:
+		return "STOCK"
+	case RealEstateFund:
+		return "FII"
+	default:
+		return ""
+	}
+}
+
+func ParseCNPJ(raw string) (string, error) {
+	var digits []int
+	for _, r := range raw {
+		switch {
+		case unicode.IsDigit(r):
+			digits = append(digits, int(r-'0'))
+		case r == '.' || r == '/' || r == '-' || r == ' ':
+		default:
+			return "", fmt.Errorf("invalid CNPJ %q", raw)
+		}
+	}
+
+	if len(digits) != 14 {
+		return "", fmt.Errorf("invalid CNPJ %q: expected 14 digits", raw)
+	}
+
+	if digits[12] != cnpjDigit(digits[:12]) || digits[13] != cnpjDigit(digits[:13]) {
+		return "", errors.New("invalid CNPJ check digits")
+	}
+
+	d := digits
+	return fmt.Sprintf("%d%d.%d%d%d.%d%d%d/%d%d%d%d-%d%d", d[0], d[1], d[2], d[3], d[4], d[5], d[6], d[7], d[8], d[9], d[10],
+		d[11], d[12], d[13]), nil
+}
+
 func NewFetcher(repository Repository, provider Provider) *Fetcher {
 	return &Fetcher{
 		Repository: repository,
@@ -47,14 +136,185 @@ func NewFetcher(repository Repository, provider Provider) *Fetcher {
 	}
 }
 
-func (f Fetcher) Fetch(ctx context.Context, symbol Symbol) error {
-	if _, err := f.Repository.GetDetails(ctx, symbol); err == nil {
+func (f Fetcher) Fetch(ctx context.Context, symbols ...Symbol) error {
+	var missing []Symbol
+
+	for _, symbol := range unique(symbols) {
+		if _, err := f.Repository.GetDetails(ctx, symbol); err != nil {
+			missing = append(missing, symbol)
+		}
+	}
+
+	if len(missing) == 0 {
 		return nil
 	}
 
-	if details, err := f.Provider.Details(ctx, symbol); err != nil {
+	infos, err := f.Provider.LastInfos(ctx, missing)
+	if err != nil && ctx.Err() != nil {
 		return err
-	} else {
-		return f.Repository.InsertDetails(ctx, details)
 	}
+
+	for _, symbol := range missing {
+		details := infos[symbol].Details
+		if details.Name == "" || details.Class == Unclassified {
+			if details, err = f.Provider.Details(ctx, symbol); err != nil {
+				return err
+			}
+		}
+
+		if details.Class == Unclassified {
+			continue
+		}
+
+		if err := f.Repository.InsertDetails(ctx, details); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func SequentialLastInfos(ctx context.Context, provider InfoProvider, symbols []Symbol) (map[Symbol]Info, error) {
+	infos := make(map[Symbol]Info, len(symbols))
+	failed := QuoteErrors{}
+
+	for _, symbol := range unique(symbols) {
+		info, err := provider.LastInfo(ctx, symbol)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			failed[symbol] = err
+			continue
+		}
+
+		infos[symbol] = info
+	}
+
+	if len(failed) > 0 {
+		return infos, failed
+	}
+
+	return infos, nil
+}
+
+func Failed(err error, symbols []Symbol) QuoteErrors {
+	if err == nil {
+		return nil
+	}
+
+	var failed QuoteErrors
+	if errors.As(err, &failed) {
+		return failed
+	}
+
+	failed = make(QuoteErrors, len(symbols))
+	for _, symbol := range symbols {
+		failed[symbol] = err
+	}
+
+	return failed
+}
+
+func (e QuoteErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for symbol, err := range e {
+		messages = append(messages, fmt.Sprintf("%s: %v", symbol, err))
+	}
+
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
+
+func (c Candles) Of(symbol Symbol) Candles {
+	var output Candles
+
+	for _, candle := range c {
+		if candle.Symbol == symbol {
+			output = append(output, candle)
+		}
+	}
+
+	return output
+}
+
+func (c Candles) Between(from, to time.Time) Candles {
+	var output Candles
+
+	for _, candle := range c {
+		if !candle.Date.Before(from) && !candle.Date.After(to) {
+			output = append(output, candle)
+		}
+	}
+
+	return output
+}
+
+func (c Candles) Covering(date time.Time) (Candle, bool) {
+	candle, found := c.At(date)
+	if !found || candle.Date.Before(LastTradingDay(date)) {
+		return Candle{}, false
+	}
+
+	return candle, true
+}
+
+func LastTradingDay(date time.Time) time.Time {
+	for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		date = date.AddDate(0, 0, -1)
+	}
+
+	return date
+}
+
+func (c Candles) At(date time.Time) (Candle, bool) {
+	var (
+		output Candle
+		found  bool
+	)
+
+	for _, candle := range c {
+		if candle.Date.After(date) {
+			continue
+		}
+
+		if !found || candle.Date.After(output.Date) {
+			output, found = candle, true
+		}
+	}
+
+	return output, found
+}
+
+func unique(symbols []Symbol) []Symbol {
+	var output []Symbol
+	seen := make(map[Symbol]bool, len(symbols))
+
+	for _, symbol := range symbols {
+		if !seen[symbol] {
+			seen[symbol] = true
+			output = append(output, symbol)
+		}
+	}
+
+	return output
+}
+
+func cnpjDigit(digits []int) int {
+	var sum int
+
+	weight := len(digits) - 7
+	for _, digit := range digits {
+		sum += digit * weight
+		if weight--; weight < 2 {
+			weight = 9
+		}
+	}
+
+	if rest := sum % 11; rest >= 2 {
+		return 11 - rest
+	}
+
+	return 0
 }
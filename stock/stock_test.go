@@ -0,0 +1,298 @@
+package stock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"stocks/currency"
+	"testing"
+	"time"
+)
+
+func TestCandles_At(t *testing.T) {
+	day1 := time.Date(2023, 12, 28, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2023, 12, 29, 0, 0, 0, 0, time.UTC)
+	day3 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	candles := Candles{
+		{Symbol: "STOCK1", Date: day2, Close: currency.New(2000)},
+		{Symbol: "STOCK1", Date: day1, Close: currency.New(1000)},
+		{Symbol: "STOCK1", Date: day3, Close: currency.New(3000)},
+	}
+
+	type args struct {
+		date time.Time
+	}
+	tests := []struct {
+		name      string
+		c         Candles
+		args      args
+		want      Candle
+		wantFound bool
+	}{
+		{
+			name: "Should return candle of the same date",
+			c:    candles,
+			args: args{
+				date: day1,
+			},
+			want:      candles[1],
+			wantFound: true,
+		},
+		{
+			name: "Should return last candle before date",
+			c:    candles,
+			args: args{
+				date: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			},
+			want:      candles[0],
+			wantFound: true,
+		},
+		{
+			name: "Should not find candle before first date",
+			c:    candles,
+			args: args{
+				date: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := tt.c.At(tt.args.date)
+			if found != tt.wantFound {
+				t.Errorf("At() found = %v, want %v", found, tt.wantFound)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("At() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCandles_Covering(t *testing.T) {
+	candles := Candles{
+		{Symbol: "STOCK1", Date: time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC), Close: currency.New(2000)},
+		{Symbol: "STOCK1", Date: time.Date(2023, 12, 29, 0, 0, 0, 0, time.UTC), Close: currency.New(2900)},
+	}
+
+	type args struct {
+		date time.Time
+	}
+	tests := []struct {
+		name      string
+		c         Candles
+		args      args
+		want      Candle
+		wantFound bool
+	}{
+		{
+			name: "Should return candle of the last trading day before a weekend",
+			c:    candles,
+			args: args{
+				date: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			},
+			want:      candles[1],
+			wantFound: true,
+		},
+		{
+			name: "Should not return a stale candle older than the last trading day",
+			c:    candles[:1],
+			args: args{
+				date: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := tt.c.Covering(tt.args.date)
+			if found != tt.wantFound {
+				t.Errorf("Covering() found = %v, want %v", found, tt.wantFound)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Covering() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeRepository struct {
+	details map[Symbol]Details
+}
+
+func (r *fakeRepository) GetDetails(_ context.Context, symbol Symbol) (Details, error) {
+	details, ok := r.details[symbol]
+	if !ok {
+		return Details{}, errors.New("not found")
+	}
+
+	return details, nil
+}
+
+func (r *fakeRepository) InsertDetails(_ context.Context, details Details) error {
+	r.details[details.Symbol] = details
+	return nil
+}
+
+func (r *fakeRepository) UpdateDetails(_ context.Context, details Details) error {
+	r.details[details.Symbol] = details
+	return nil
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	var calls int
+	provider := batchProvider{
+		infos: map[Symbol]Info{
+			"STOCK2": {Symbol: "STOCK2", Details: Details{Symbol: "STOCK2", Name: "Stock 2"}},
+			"FUND11": {Symbol: "FUND11", Details: Details{Symbol: "FUND11", Class: Unclassified, Name: "Fund"}},
+		},
+		calls: &calls,
+	}
+	repository := &fakeRepository{
+		details: map[Symbol]Details{"STOCK1": {Symbol: "STOCK1", Name: "Stock 1"}},
+	}
+
+	if err := NewFetcher(repository, provider).Fetch(context.Background(), "STOCK1", "STOCK2", "STOCK2", "FUND11"); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	want := map[Symbol]Details{
+		"STOCK1": {Symbol: "STOCK1", Name: "Stock 1"},
+		"STOCK2": {Symbol: "STOCK2", Name: "Stock 2"},
+	}
+	if !reflect.DeepEqual(repository.details, want) {
+		t.Errorf("Fetch() details = %v, want %v", repository.details, want)
+	}
+
+	if calls != 1 {
+		t.Errorf("Fetch() calls = %v, want 1", calls)
+	}
+}
+
+func TestParseCNPJ(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Should format digits only CNPJ",
+			raw:     "33000167000101",
+			want:    "33.000.167/0001-01",
+			wantErr: false,
+		},
+		{
+			name:    "Should accept formatted CNPJ",
+			raw:     "60.746.948/0001-12",
+			want:    "60.746.948/0001-12",
+			wantErr: false,
+		},
+		{
+			name:    "Should return error if check digits are wrong",
+			raw:     "33.000.167/0001-02",
+			wantErr: true,
+		},
+		{
+			name:    "Should return error if CNPJ is incomplete",
+			raw:     "33.000.167",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCNPJ(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCNPJ() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseCNPJ() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSymbol_Underlying(t *testing.T) {
+	tests := []struct {
+		name        string
+		s           Symbol
+		want        Symbol
+		wantReceipt Symbol
+		wantRight   Symbol
+	}{
+		{
+			name:        "Should link common stock rights to receipt and underlying",
+			s:           "PETR1",
+			want:        "PETR3",
+			wantReceipt: "PETR9",
+		},
+		{
+			name:      "Should link preferred stock receipts to right and underlying",
+			s:         "PETR10",
+			want:      "PETR4",
+			wantRight: "PETR2",
+		},
+		{
+			name:        "Should link fund rights to receipt and underlying",
+			s:           "FUND12",
+			want:        "FUND11",
+			wantReceipt: "FUND13",
+		},
+		{
+			name: "Should keep regular symbols as their own underlying",
+			s:    "FUND11",
+			want: "FUND11",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Underlying(); got != tt.want {
+				t.Errorf("Underlying() = %v, want %v", got, tt.want)
+			}
+			if got := tt.s.Receipt(); got != tt.wantReceipt {
+				t.Errorf("Receipt() = %v, want %v", got, tt.wantReceipt)
+			}
+			if got := tt.s.Right(); got != tt.wantRight {
+				t.Errorf("Right() = %v, want %v", got, tt.wantRight)
+			}
+		})
+	}
+}
+
+func TestSymbol_IsShare(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Symbol
+		want bool
+	}{
+		{
+			name: "Should recognize common and preferred shares",
+			s:    "PETR4",
+			want: true,
+		},
+		{
+			name: "Should not recognize rights as shares",
+			s:    "PETR1",
+		},
+		{
+			name: "Should not recognize receipts as shares",
+			s:    "PETR9",
+		},
+		{
+			name: "Should not recognize units and ETFs as shares",
+			s:    "BOVA11",
+		},
+		{
+			name: "Should keep symbols outside the B3 pattern as shares",
+			s:    "STOCK1",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.IsShare(); got != tt.want {
+				t.Errorf("IsShare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package stock
+
+import (
+	"strconv"
+	"strings"
+)
+
+type (
+	subscription struct {
+		right      int
+		receipt    int
+		underlying int
+	}
+)
+
+var (
+	subscriptions = []subscription{
+		{right: 1, receipt: 9, underlying: 3},
+		{right: 2, receipt: 10, underlying: 4},
+		{right: 12, receipt: 13, underlying: 11},
+	}
+)
+
+func (s Symbol) IsReceipt() bool {
+	_, ok := s.subscription(func(sub subscription) int { return sub.receipt })
+	return ok
+}
+
+func (s Symbol) Right() Symbol {
+	if sub, ok := s.subscription(func(sub subscription) int { return sub.receipt }); ok {
+		return s.with(sub.right)
+	}
+
+	return ""
+}
+
+func (s Symbol) Receipt() Symbol {
+	if sub, ok := s.subscription(func(sub subscription) int { return sub.right }); ok {
+		return s.with(sub.receipt)
+	}
+
+	return ""
+}
+
+func (s Symbol) Underlying() Symbol {
+	if sub, ok := s.subscription(func(sub subscription) int { return sub.right }); ok {
+		return s.with(sub.underlying)
+	}
+
+	if sub, ok := s.subscription(func(sub subscription) int { return sub.receipt }); ok {
+		return s.with(sub.underlying)
+	}
+
+	return s
+}
+
+func (s Symbol) IsShare() bool {
+	_, number, ok := s.split()
+	return !ok || (number >= 3 && number <= 8)
+}
+
+func (s Symbol) subscription(kind func(subscription) int) (subscription, bool) {
+	_, number, ok := s.split()
+	if !ok {
+		return subscription{}, false
+	}
+
+	for _, sub := range subscriptions {
+		if kind(sub) == number {
+			return sub, true
+		}
+	}
+
+	return subscription{}, false
+}
+
+func (s Symbol) with(number int) Symbol {
+	root, _, _ := s.split()
+	return Symbol(root + strconv.Itoa(number))
+}
+
+func (s Symbol) split() (string, int, bool) {
+	root := strings.TrimRightFunc(string(s), func(r rune) bool { return r >= '0' && r <= '9' })
+	if len(root) != 4 {
+		return "", 0, false
+	}
+
+	number, err := strconv.Atoi(string(s)[len(root):])
+	if err != nil {
+		return "", 0, false
+	}
+
+	return root, number, true
+}
@@ -0,0 +1,227 @@
+package tax
+
+import (
+	"fmt"
+	"io"
+	"stocks/currency"
+	"stocks/operation"
+	"stocks/separator"
+	"stocks/stock"
+	"time"
+)
+
+const (
+	SwingTrade Category = iota
+	DayTrade
+	RealEstateFund
+
+	DarfCode = "6015"
+
+	periodLayout = "2006-01"
+)
+
+var (
+	exemptionLimit = currency.New(2000000)
+	minimumDarf    = currency.New(1000)
+)
+
+type (
+	Category int
+
+	Result struct {
+		Category    Category
+		Sales       currency.Currency
+		Gain        currency.Currency
+		Exempt      bool
+		ExemptGain  currency.Currency
+		Compensated currency.Currency
+		Base        currency.Currency
+		Tax         currency.Currency
+		CarriedLoss currency.Currency
+	}
+
+	Report struct {
+		Period      time.Time
+		SwingTrade  Result
+		DayTrade    Result
+		RealEstate  Result
+		WithheldTax currency.Currency
+		Carried     currency.Currency
+		Due         currency.Currency
+	}
+
+	balance struct {
+		losses      map[Category]currency.Currency
+		withheldTax currency.Currency
+		carried     currency.Currency
+	}
+)
+
+func (c Category) String() string {
+	switch c {
+	case SwingTrade:
+		return "Swing Trade"
+	case DayTrade:
+		return "Day Trade"
+	case RealEstateFund:
+		return "FII"
+	default:
+		return ""
+	}
+}
+
+func (c Category) Rate() int64 {
+	switch c {
+	case DayTrade, RealEstateFund:
+		return 20
+	default:
+		return 15
+	}
+}
+
+func Calculate(operations operation.List, classes map[stock.Symbol]stock.Class, period time.Time) Report {
+	period = time.Date(period.Year(), period.Month(), 1, 0, 0, 0, 0, time.UTC)
+	sales := operations.Sales()
+
+	b := balance{
+		losses: map[Category]currency.Currency{},
+	}
+
+	var report Report
+	for month := firstMonth(sales, period); !month.After(period); month = month.AddDate(0, 1, 0) {
+		if month.Month() == time.January {
+			b.withheldTax = currency.Currency{}
+		}
+
+		report = b.calculate(month, sales.Between(month, month.AddDate(0, 1, 0)), classes)
+	}
+
+	return report
+}
+
+func (b *balance) calculate(period time.Time, sales operation.Sales, classes map[stock.Symbol]stock.Class) Report {
+	report := Report{
+		Period:     period,
+		SwingTrade: Result{Category: SwingTrade},
+		DayTrade:   Result{Category: DayTrade},
+		RealEstate: Result{Category: RealEstateFund},
+	}
+
+	var shares, sharesGain currency.Currency
+	for _, sale := range sales {
+		result := &report.SwingTrade
+		switch {
+		case classes[sale.Symbol] == stock.RealEstateFund:
+			result = &report.RealEstate
+		case sale.DayTrade:
+			result = &report.DayTrade
+		case sale.Symbol.IsShare():
+			shares = shares.Add(sale.Amount)
+			sharesGain = sharesGain.Add(sale.Result())
+		}
+
+		result.Sales = result.Sales.Add(sale.Amount)
+		result.Gain = result.Gain.Add(sale.Result())
+		report.WithheldTax = report.WithheldTax.Add(sale.WithheldTax)
+	}
+
+	if shares.Cmp(exemptionLimit) <= 0 && sharesGain.IsPositive() {
+		report.SwingTrade.Exempt, report.SwingTrade.ExemptGain = true, sharesGain
+	}
+
+	b.apply(&report.SwingTrade)
+	b.apply(&report.DayTrade)
+	b.apply(&report.RealEstate)
+
+	tax := report.SwingTrade.Tax.Add(report.DayTrade.Tax).Add(report.RealEstate.Tax)
+	withheldTax := report.WithheldTax.Add(b.withheldTax)
+	report.Carried = b.carried
+
+	due := tax.Sub(withheldTax)
+	if due.IsNegative() {
+		b.withheldTax = due.Neg()
+		due = currency.Currency{}
+	} else {
+		b.withheldTax = currency.Currency{}
+	}
+
+	due = due.Add(b.carried)
+	if due.Cmp(minimumDarf) < 0 {
+		b.carried = due
+		due = currency.Currency{}
+	} else {
+		b.carried = currency.Currency{}
+	}
+
+	report.Due = due
+	return report
+}
+
+func (b *balance) apply(result *Result) {
+	loss := b.losses[result.Category]
+	gain := result.Gain.Sub(result.ExemptGain)
+
+	switch {
+	case gain.IsNegative():
+		loss = loss.Sub(gain)
+	default:
+		result.Compensated = loss
+		if gain.Cmp(loss) < 0 {
+			result.Compensated = gain
+		}
+
+		loss = loss.Sub(result.Compensated)
+		result.Base = gain.Sub(result.Compensated)
+		result.Tax = result.Base.MulRatio(result.Category.Rate(), 100)
+	}
+
+	result.CarriedLoss = loss
+	b.losses[result.Category] = loss
+}
+
+func (r Result) label() string {
+	switch {
+	case !r.Exempt:
+		return r.Category.String()
+	case r.ExemptGain == r.Gain:
+		return fmt.Sprintf("%s (exempt)", r.Category)
+	default:
+		return fmt.Sprintf("%s (%s exempt)", r.Category, r.ExemptGain)
+	}
+}
+
+func (r Report) Print(writer io.Writer, sep separator.Separator) error {
+	title := fmt.Sprintf("Period%s%s\n\nCategory%sSales%sResult%sCompensated%sBase%sTax%sCarried Loss\n",
+		sep, r.Period.Format(periodLayout), sep, sep, sep, sep, sep, sep)
+	if _, err := io.WriteString(writer, title); err != nil {
+		return err
+	}
+
+	for _, result := range []Result{r.SwingTrade, r.DayTrade, r.RealEstate} {
+		line := fmt.Sprintf("%s%s%s%s%s%s%s%s%s%s%s%s%s\n",
+			result.label(), sep, result.Sales, sep, result.Gain, sep, result.Compensated, sep, result.Base, sep,
+			result.Tax, sep, result.CarriedLoss)
+
+		if _, err := io.WriteString(writer, line); err != nil {
+			return err
+		}
+	}
+
+	summary := fmt.Sprintf("\nIRRF%s%s\nCarried%s%s\nDARF %s%s%s\n",
+		sep, r.WithheldTax, sep, r.Carried, DarfCode, sep, r.Due)
+	_, err := io.WriteString(writer, summary)
+	return err
+}
+
+func firstMonth(sales operation.Sales, period time.Time) time.Time {
+	first := period
+
+	for _, sale := range sales {
+		month := time.Date(sale.Date.Year(), sale.Date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if month.Before(first) {
+			first = month
+		}
+	}
+
+	return first
+}
@@ -0,0 +1,133 @@
+package tax
+
+import (
+	"reflect"
+	"stocks/currency"
+	"stocks/operation"
+	"stocks/stock"
+	"testing"
+	"time"
+)
+
+func TestCalculate(t *testing.T) {
+	classes := map[stock.Symbol]stock.Class{
+		"STOCK1": stock.Stock,
+		"FUND11": stock.RealEstateFund,
+	}
+
+	type args struct {
+		operations operation.List
+		period     time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want Report
+	}{
+		{
+			name: "Should exempt swing trade gains when sales are below the limit",
+			args: args{
+				operations: operation.List{
+					{Symbol: "STOCK1", Type: operation.Buy, Quantity: 100, UnitValue: currency.NewFromFloat(100), Date: time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Sell, Quantity: 100, UnitValue: currency.NewFromFloat(150), Date: time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC)},
+				},
+				period: time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want: Report{
+				Period:     time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC),
+				SwingTrade: Result{Category: SwingTrade, Sales: currency.NewFromFloat(15000), Gain: currency.NewFromFloat(5000), Exempt: true, ExemptGain: currency.NewFromFloat(5000)},
+				DayTrade:   Result{Category: DayTrade},
+				RealEstate: Result{Category: RealEstateFund},
+			},
+		},
+		{
+			name: "Should exempt only common stock gains and keep other sales out of the limit",
+			args: args{
+				operations: operation.List{
+					{Symbol: "STOC3", Type: operation.Buy, Quantity: 100, UnitValue: currency.NewFromFloat(100), Date: time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOC3", Type: operation.Sell, Quantity: 100, UnitValue: currency.NewFromFloat(150), Date: time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "BOVA11", Type: operation.Buy, Quantity: 100, UnitValue: currency.NewFromFloat(100), Date: time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "BOVA11", Type: operation.Sell, Quantity: 100, UnitValue: currency.NewFromFloat(110), Date: time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOC1", Type: operation.Buy, Quantity: 100, UnitValue: currency.NewFromFloat(1), Date: time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOC1", Type: operation.Sell, Quantity: 100, UnitValue: currency.NewFromFloat(2), Date: time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC)},
+				},
+				period: time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want: Report{
+				Period: time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC),
+				SwingTrade: Result{Category: SwingTrade, Sales: currency.NewFromFloat(26200), Gain: currency.NewFromFloat(6100), Exempt: true,
+					ExemptGain: currency.NewFromFloat(5000), Base: currency.NewFromFloat(1100), Tax: currency.NewFromFloat(165)},
+				DayTrade:   Result{Category: DayTrade},
+				RealEstate: Result{Category: RealEstateFund},
+				Due:        currency.NewFromFloat(165),
+			},
+		},
+		{
+			name: "Should compensate previous losses and deduct withheld tax",
+			args: args{
+				operations: operation.List{
+					{Symbol: "STOCK1", Type: operation.Buy, Quantity: 1000, UnitValue: currency.NewFromFloat(30), Date: time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Sell, Quantity: 500, UnitValue: currency.NewFromFloat(28), Date: time.Date(2022, 2, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Sell, Quantity: 500, UnitValue: currency.NewFromFloat(50), WithheldTax: currency.NewFromFloat(1.25), Date: time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC)},
+				},
+				period: time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want: Report{
+				Period:      time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC),
+				SwingTrade:  Result{Category: SwingTrade, Sales: currency.NewFromFloat(25000), Gain: currency.NewFromFloat(10000), Compensated: currency.NewFromFloat(1000), Base: currency.NewFromFloat(9000), Tax: currency.NewFromFloat(1350)},
+				DayTrade:    Result{Category: DayTrade},
+				RealEstate:  Result{Category: RealEstateFund},
+				WithheldTax: currency.NewFromFloat(1.25),
+				Due:         currency.NewFromFloat(1348.75),
+			},
+		},
+		{
+			name: "Should tax day trade without exemption and add carried small amounts",
+			args: args{
+				operations: operation.List{
+					{Symbol: "STOCK1", Type: operation.Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Sell, Quantity: 10, UnitValue: currency.NewFromFloat(12), Date: time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: time.Date(2022, 5, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Sell, Quantity: 10, UnitValue: currency.NewFromFloat(14), Date: time.Date(2022, 5, 10, 0, 0, 0, 0, time.UTC)},
+				},
+				period: time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want: Report{
+				Period:     time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC),
+				SwingTrade: Result{Category: SwingTrade},
+				DayTrade:   Result{Category: DayTrade, Sales: currency.NewFromFloat(140), Gain: currency.NewFromFloat(40), Base: currency.NewFromFloat(40), Tax: currency.NewFromFloat(8)},
+				RealEstate: Result{Category: RealEstateFund},
+				Carried:    currency.NewFromFloat(4),
+				Due:        currency.NewFromFloat(12),
+			},
+		},
+		{
+			name: "Should tax real estate fund gains at 20% without exemption or stock loss compensation",
+			args: args{
+				operations: operation.List{
+					{Symbol: "STOCK1", Type: operation.Buy, Quantity: 100, UnitValue: currency.NewFromFloat(10), Date: time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Sell, Quantity: 100, UnitValue: currency.NewFromFloat(8), Date: time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "FUND11", Type: operation.Buy, Quantity: 100, UnitValue: currency.NewFromFloat(100), Date: time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "FUND11", Type: operation.Sell, Quantity: 100, UnitValue: currency.NewFromFloat(110), Date: time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Buy, Quantity: 100, UnitValue: currency.NewFromFloat(10), Date: time.Date(2022, 4, 11, 0, 0, 0, 0, time.UTC)},
+					{Symbol: "STOCK1", Type: operation.Sell, Quantity: 100, UnitValue: currency.NewFromFloat(15), Date: time.Date(2022, 4, 20, 0, 0, 0, 0, time.UTC)},
+				},
+				period: time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want: Report{
+				Period:     time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC),
+				SwingTrade: Result{Category: SwingTrade, Sales: currency.NewFromFloat(1500), Gain: currency.NewFromFloat(500), Exempt: true, ExemptGain: currency.NewFromFloat(500), CarriedLoss: currency.NewFromFloat(200)},
+				DayTrade:   Result{Category: DayTrade},
+				RealEstate: Result{Category: RealEstateFund, Sales: currency.NewFromFloat(11000), Gain: currency.NewFromFloat(1000), Base: currency.NewFromFloat(1000), Tax: currency.NewFromFloat(200)},
+				Due:        currency.NewFromFloat(200),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Calculate(tt.args.operations, classes, tt.args.period); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Calculate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"sort"
+	"stocks/income"
+	"stocks/internal/b3"
+	"stocks/operation"
+	"stocks/portfolio"
+	"strings"
+)
+
+type (
+	B3ImportResponse struct {
+		Operations operation.Reconciliation
+		Incomes    income.List
+		Ignored    map[string]int
+	}
+
+	StatementRepository interface {
+		CreateStatement(ctx context.Context, operations operation.List, incomes income.List) error
+	}
+
+	B3ImportUseCase struct {
+		Fetcher    Fetcher
+		Repository operation.Repository
+		Incomes    income.Repository
+		Portfolios portfolio.Repository
+		Statements StatementRepository
+	}
+)
+
+func NewB3ImportUseCase(repository operation.Repository, incomes income.Repository, portfolios portfolio.Repository,
+	statements StatementRepository, fetcher Fetcher) *B3ImportUseCase {
+	return &B3ImportUseCase{
+		Fetcher:    fetcher,
+		Repository: repository,
+		Incomes:    incomes,
+		Portfolios: portfolios,
+		Statements: statements,
+	}
+}
+
+func (uc B3ImportUseCase) Execute(ctx context.Context, reader io.Reader, name string, dryRun bool) (B3ImportResponse, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return B3ImportResponse{}, err
+	}
+
+	portfolioOf, err := uc.resolver(ctx, name)
+	if err != nil {
+		return B3ImportResponse{}, err
+	}
+
+	statement, err := b3.Parse(data, portfolioOf)
+	if err != nil {
+		return B3ImportResponse{}, err
+	}
+
+	existing, err := uc.Repository.List(ctx)
+	if err != nil {
+		return B3ImportResponse{}, err
+	}
+
+	operations, err := resolveEvents(existing, statement.Operations)
+	if err != nil {
+		return B3ImportResponse{}, err
+	}
+
+	incomes, err := uc.Incomes.Incomes(ctx)
+	if err != nil {
+		return B3ImportResponse{}, err
+	}
+
+	response := B3ImportResponse{
+		Operations: operations.Reconcile(existing),
+		Incomes:    statement.Incomes.Missing(incomes),
+		Ignored:    statement.Ignored,
+	}
+
+	if err := oversold(existing, response.Operations.Inserted, operations, nil); err != nil {
+		return B3ImportResponse{}, err
+	}
+
+	if dryRun {
+		return response, nil
+	}
+
+	symbols := fetchable(response.Operations.Inserted)
+
+	for _, i := range response.Incomes {
+		symbols = append(symbols, i.Symbol)
+	}
+
+	if err := uc.Fetcher.Fetch(ctx, symbols...); err != nil {
+		return B3ImportResponse{}, err
+	}
+
+	if err := uc.Statements.CreateStatement(ctx, response.Operations.Inserted, response.Incomes); err != nil {
+		return B3ImportResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (uc B3ImportUseCase) resolver(ctx context.Context, name string) (b3.Resolver, error) {
+	if name != "" {
+		return func(string) string {
+			return name
+		}, nil
+	}
+
+	portfolios, err := uc.Portfolios.Portfolios(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(broker string) string {
+		for _, p := range portfolios {
+			if p.Broker != "" && strings.EqualFold(p.Broker, broker) {
+				return p.Name
+			}
+		}
+
+		return portfolio.Default
+	}, nil
+}
+
+func resolveEvents(existing, imported operation.List) (operation.List, error) {
+	sort.SliceStable(imported, func(i, j int) bool {
+		return imported[i].Date.Before(imported[j].Date)
+	})
+
+	stored := map[string]int{}
+	for _, o := range existing {
+		stored[o.Fingerprint()]++
+	}
+
+	all := existing
+	add := func(o operation.Operation) {
+		if key := o.Fingerprint(); stored[key] > 0 {
+			stored[key]--
+		} else {
+			all = all.With(o)
+		}
+	}
+
+	for _, o := range imported {
+		if !o.Type.IsEvent() {
+			add(o)
+		}
+	}
+
+	output := make(operation.List, len(imported))
+	for i, o := range imported {
+		if o.Type.IsEvent() {
+			resolved, err := all.ResolveEvent(o)
+			if err != nil {
+				return nil, err
+			}
+
+			add(resolved)
+			o = resolved
+		}
+
+		output[i] = o
+	}
+
+	return output, nil
+}
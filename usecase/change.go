@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"stocks/operation"
+	"stocks/portfolio"
+)
+
+type (
+	EditRequest struct {
+		ID     uint
+		Fields map[string]string
+	}
+
+	EditOperationUseCase struct {
+		Fetcher    Fetcher
+		Repository operation.Repository
+	}
+
+	DeleteOperationUseCase struct {
+		Repository operation.Repository
+	}
+
+	ChangesUseCase struct {
+		Repository operation.Repository
+	}
+)
+
+func NewEditOperationUseCase(repository operation.Repository, fetcher Fetcher) *EditOperationUseCase {
+	return &EditOperationUseCase{
+		Fetcher:    fetcher,
+		Repository: repository,
+	}
+}
+
+func NewDeleteOperationUseCase(repository operation.Repository) *DeleteOperationUseCase {
+	return &DeleteOperationUseCase{
+		Repository: repository,
+	}
+}
+
+func NewChangesUseCase(repository operation.Repository) *ChangesUseCase {
+	return &ChangesUseCase{
+		Repository: repository,
+	}
+}
+
+func (uc EditOperationUseCase) Execute(ctx context.Context, request EditRequest) (operation.Operation, error) {
+	previous, err := uc.Repository.Get(ctx, request.ID)
+	if err != nil {
+		return operation.Operation{}, err
+	}
+
+	op, err := previous.Patch(request.Fields)
+	if err != nil {
+		return operation.Operation{}, err
+	}
+
+	if op.Type.IsEvent() {
+		if err := validateEvent(op.Type, op.Ratio); err != nil {
+			return operation.Operation{}, err
+		}
+
+		op.Portfolio = ""
+	} else {
+		op.Portfolio = portfolio.OrDefault(op.Portfolio)
+	}
+
+	operations, err := uc.Repository.List(ctx)
+	if err != nil {
+		return operation.Operation{}, err
+	}
+
+	if o := oversells(operations, without(operations, op.ID).With(op)); len(o) > 0 {
+		return operation.Operation{}, oversellError(o[0])
+	}
+
+	if op.Symbol != previous.Symbol {
+		if err := uc.Fetcher.Fetch(ctx, op.Symbol); err != nil {
+			return operation.Operation{}, err
+		}
+	}
+
+	if err := uc.Repository.Update(ctx, op); err != nil {
+		return operation.Operation{}, err
+	}
+
+	return op, nil
+}
+
+func (uc DeleteOperationUseCase) Execute(ctx context.Context, id uint) (operation.Operation, error) {
+	op, err := uc.Repository.Get(ctx, id)
+	if err != nil {
+		return operation.Operation{}, err
+	}
+
+	operations, err := uc.Repository.List(ctx)
+	if err != nil {
+		return operation.Operation{}, err
+	}
+
+	if o := oversells(operations, without(operations, id)); len(o) > 0 {
+		return operation.Operation{}, oversellError(o[0])
+	}
+
+	if err := uc.Repository.Delete(ctx, id); err != nil {
+		return operation.Operation{}, err
+	}
+
+	return op, nil
+}
+
+func (uc ChangesUseCase) Execute(ctx context.Context) (operation.Changes, error) {
+	return uc.Repository.Changes(ctx)
+}
+
+func without(operations operation.List, id uint) operation.List {
+	var output operation.List
+	for _, op := range operations {
+		if op.ID != id {
+			output = append(output, op)
+		}
+	}
+
+	return output
+}
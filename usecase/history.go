@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"stocks/asset"
+	"stocks/stock"
+	"time"
+)
+
+const (
+	historyWindow = 15
+)
+
+type (
+	HistoricalAssetsUseCase struct {
+		Provider   stock.Provider
+		Candles    stock.CandleRepository
+		Repository asset.Repository
+	}
+)
+
+func NewHistoricalAssetsUseCase(provider stock.Provider, candles stock.CandleRepository, repository asset.Repository) *HistoricalAssetsUseCase {
+	return &HistoricalAssetsUseCase{
+		Provider:   provider,
+		Candles:    candles,
+		Repository: repository,
+	}
+}
+
+func (uc HistoricalAssetsUseCase) Execute(ctx context.Context, portfolio string, date time.Time) (asset.Assets, error) {
+	assets, err := uc.Repository.AssetsAt(ctx, portfolio, date)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range assets {
+		if assets[i].Quantity == 0 {
+			continue
+		}
+
+		candle, err := uc.closing(ctx, assets[i].Symbol, date)
+		if err != nil {
+			assets[i].QuoteError = err
+			continue
+		}
+
+		assets[i].LastPrice = candle.Close
+	}
+
+	return assets, nil
+}
+
+func (uc HistoricalAssetsUseCase) closing(ctx context.Context, symbol stock.Symbol, date time.Time) (stock.Candle, error) {
+	from := date.AddDate(0, 0, -historyWindow)
+
+	candles, err := uc.Candles.Candles(ctx, symbol, from, date)
+	if err != nil {
+		return stock.Candle{}, err
+	}
+
+	if candle, ok := candles.Covering(date); ok {
+		return candle, nil
+	}
+
+	if candles, err = uc.Provider.History(ctx, symbol, from, date); err != nil {
+		return stock.Candle{}, err
+	}
+
+	if err := uc.Candles.InsertCandles(ctx, candles); err != nil {
+		return stock.Candle{}, err
+	}
+
+	candle, ok := candles.At(date)
+	if !ok {
+		return stock.Candle{}, fmt.Errorf("no price history for %s at %s", symbol, date.Format("2006-01-02"))
+	}
+
+	return candle, nil
+}
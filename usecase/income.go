@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"stocks/csv"
+	"stocks/income"
+	"stocks/portfolio"
+	"stocks/stock"
+	"time"
+)
+
+type (
+	IncomeImportUseCase struct {
+		Fetcher    Fetcher
+		Repository income.Repository
+	}
+
+	IncomeReportUseCase struct {
+		Repository income.Repository
+	}
+)
+
+func NewIncomeImportUseCase(repository income.Repository, fetcher Fetcher) *IncomeImportUseCase {
+	return &IncomeImportUseCase{
+		Fetcher:    fetcher,
+		Repository: repository,
+	}
+}
+
+func NewIncomeReportUseCase(repository income.Repository) *IncomeReportUseCase {
+	return &IncomeReportUseCase{
+		Repository: repository,
+	}
+}
+
+func (uc IncomeImportUseCase) Execute(ctx context.Context, reader io.Reader, name string) (income.List, error) {
+	incomes, err := csv.Import(reader, true, income.ParseFromCSV)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]stock.Symbol, len(incomes))
+	for i := range incomes {
+		symbols[i] = incomes[i].Symbol
+		if incomes[i].Portfolio == "" {
+			incomes[i].Portfolio = portfolio.OrDefault(name)
+		}
+	}
+
+	if err := uc.Fetcher.Fetch(ctx, symbols...); err != nil {
+		return nil, err
+	}
+
+	if err := uc.Repository.InsertIncomes(ctx, incomes); err != nil {
+		return nil, err
+	}
+
+	return incomes, nil
+}
+
+func (uc IncomeReportUseCase) Execute(ctx context.Context, portfolio string, from, to time.Time) (income.Report, error) {
+	incomes, err := uc.Repository.Incomes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return incomes.In(portfolio).Between(from, to).Report(), nil
+}
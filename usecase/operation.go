@@ -2,26 +2,57 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"sort"
 	"stocks/asset"
 	"stocks/csv"
 	"stocks/currency"
+	"stocks/internal/sinacor"
 	"stocks/operation"
+	"stocks/portfolio"
 	"stocks/stock"
-	"sync"
 	"time"
 )
 
 type (
 	Fetcher interface {
-		Fetch(ctx context.Context, symbol stock.Symbol) error
+		Fetch(ctx context.Context, symbols ...stock.Symbol) error
 	}
 
 	BuyRequest struct {
 		Symbol    stock.Symbol
 		Quantity  int
-		UnitValue float64
+		UnitValue currency.Currency
+		Fees      operation.Fees
 		Date      time.Time
+		Portfolio string
+	}
+
+	SellRequest struct {
+		Symbol    stock.Symbol
+		Quantity  int
+		UnitValue currency.Currency
+		Fees      operation.Fees
+		Date      time.Time
+		Portfolio string
+		Lot       time.Time
+	}
+
+	SellResponse struct {
+		Operation    operation.Operation
+		AveragePrice currency.Currency
+		Gain         currency.Currency
+		DayTradeGain currency.Currency
+	}
+
+	EventRequest struct {
+		Type      operation.Type
+		Symbol    stock.Symbol
+		Ratio     float64
+		UnitValue currency.Currency
+		Date      time.Time
+		Target    stock.Symbol
 	}
 
 	BuyOperationUseCase struct {
@@ -29,10 +60,29 @@ type (
 		Repository operation.Repository
 	}
 
+	SellOperationUseCase struct {
+		Repository operation.Repository
+		Portfolios portfolio.Repository
+	}
+
+	EventOperationUseCase struct {
+		Repository operation.Repository
+	}
+
 	ListUseCase struct {
 		Repository operation.Repository
 	}
 
+	PositionHistoryUseCase struct {
+		Repository operation.Repository
+		Portfolios portfolio.Repository
+	}
+
+	RealizedUseCase struct {
+		Repository operation.Repository
+		Portfolios portfolio.Repository
+	}
+
 	ImportUseCase struct {
 		Fetcher    Fetcher
 		Repository operation.Repository
@@ -51,12 +101,39 @@ func NewBuyOperationUseCase(repository operation.Repository, fetcher Fetcher) *B
 	}
 }
 
+func NewSellOperationUseCase(repository operation.Repository, portfolios portfolio.Repository) *SellOperationUseCase {
+	return &SellOperationUseCase{
+		Repository: repository,
+		Portfolios: portfolios,
+	}
+}
+
+func NewEventOperationUseCase(repository operation.Repository) *EventOperationUseCase {
+	return &EventOperationUseCase{
+		Repository: repository,
+	}
+}
+
 func NewListUseCase(repository operation.Repository) *ListUseCase {
 	return &ListUseCase{
 		Repository: repository,
 	}
 }
 
+func NewPositionHistoryUseCase(repository operation.Repository, portfolios portfolio.Repository) *PositionHistoryUseCase {
+	return &PositionHistoryUseCase{
+		Repository: repository,
+		Portfolios: portfolios,
+	}
+}
+
+func NewRealizedUseCase(repository operation.Repository, portfolios portfolio.Repository) *RealizedUseCase {
+	return &RealizedUseCase{
+		Repository: repository,
+		Portfolios: portfolios,
+	}
+}
+
 func NewImportUseCase(repository operation.Repository, fetcher Fetcher) *ImportUseCase {
 	return &ImportUseCase{
 		Fetcher:    fetcher,
@@ -72,16 +149,149 @@ func NewAssetsUseCase(provider stock.Provider, repository asset.Repository) *Ass
 }
 
 func (uc BuyOperationUseCase) Execute(ctx context.Context, request BuyRequest) (operation.Operation, error) {
+	op := operation.Operation{
+		Type:      operation.Buy,
+		Symbol:    request.Symbol,
+		Date:      request.Date,
+		Quantity:  request.Quantity,
+		UnitValue: request.UnitValue,
+		Fees:      request.Fees,
+		Portfolio: portfolio.OrDefault(request.Portfolio),
+	}
+
+	if err := op.Validate(); err != nil {
+		return operation.Operation{}, err
+	}
+
 	if err := uc.Fetcher.Fetch(ctx, request.Symbol); err != nil {
 		return operation.Operation{}, err
 	}
 
+	if err := uc.Repository.Create(ctx, op); err != nil {
+		return operation.Operation{}, err
+	}
+
+	return op, nil
+}
+
+func (uc SellOperationUseCase) Execute(ctx context.Context, request SellRequest) (SellResponse, error) {
+	name := portfolio.OrDefault(request.Portfolio)
 	op := operation.Operation{
-		Type:      operation.Buy,
+		Type:      operation.Sell,
 		Symbol:    request.Symbol,
 		Date:      request.Date,
 		Quantity:  request.Quantity,
 		UnitValue: request.UnitValue,
+		Fees:      request.Fees,
+		Portfolio: name,
+		Lot:       request.Lot,
+	}
+
+	if err := op.Validate(); err != nil {
+		return SellResponse{}, err
+	}
+
+	operations, err := uc.Repository.List(ctx)
+	if err != nil {
+		return SellResponse{}, err
+	}
+
+	methods, err := methods(ctx, uc.Portfolios)
+	if err != nil {
+		return SellResponse{}, err
+	}
+
+	operations = operations.In(name)
+
+	position := operations.Until(request.Date).Ledger(methods).Position(request.Symbol)
+	available := position.Quantity
+	if current := operations.Ledger(methods).Position(request.Symbol).Quantity; current < available {
+		available = current
+	}
+
+	if request.Quantity > available {
+		return SellResponse{}, fmt.Errorf("cannot sell %d %s: current position in %s is %d",
+			request.Quantity, request.Symbol, name, available)
+	}
+
+	if err := validateLot(operations, methods, name, request); err != nil {
+		return SellResponse{}, err
+	}
+
+	if err := uc.Repository.Create(ctx, op); err != nil {
+		return SellResponse{}, err
+	}
+
+	from, to := request.Date, request.Date.AddDate(0, 0, 1)
+	before := operations.Ledger(methods).Sales().Of(request.Symbol).Between(from, to)
+	after := operations.With(op).Ledger(methods).Sales().Of(request.Symbol).Between(from, to)
+
+	return SellResponse{
+		Operation:    op,
+		AveragePrice: position.AveragePrice,
+		Gain:         after.SwingTrades().Result().Sub(before.SwingTrades().Result()),
+		DayTradeGain: after.DayTrades().Result().Sub(before.DayTrades().Result()),
+	}, nil
+}
+
+func validateLot(operations operation.List, methods operation.Methods, name string, request SellRequest) error {
+	method := methods[name]
+	if method != operation.SpecificLot {
+		if !request.Lot.IsZero() {
+			return fmt.Errorf("portfolio %s uses %s cost basis: --lot requires specific lot identification", name, method)
+		}
+
+		return nil
+	}
+
+	if request.Lot.IsZero() {
+		return fmt.Errorf("portfolio %s uses specific lot identification: use --lot <date> to pick the lot being sold", name)
+	}
+
+	held := lotQuantity(operations.Until(request.Date).Ledger(methods).Lots(request.Symbol), request.Lot)
+	if current := lotQuantity(operations.Ledger(methods).Lots(request.Symbol), request.Lot); current < held {
+		held = current
+	}
+
+	if request.Quantity > held {
+		return fmt.Errorf("cannot sell %d %s from lot %s: the lot holds %d in %s",
+			request.Quantity, request.Symbol, request.Lot.Format("2006-01-02"), held, name)
+	}
+
+	return nil
+}
+
+func lotQuantity(lots []operation.Lot, date time.Time) int {
+	var quantity int
+	for _, lot := range lots {
+		if lot.Date.Equal(date) {
+			quantity += lot.Quantity
+		}
+	}
+
+	return quantity
+}
+
+func (uc EventOperationUseCase) Execute(ctx context.Context, request EventRequest) (operation.Operation, error) {
+	if !request.Type.IsEvent() {
+		return operation.Operation{}, fmt.Errorf("%s is not a corporate event", request.Type)
+	}
+
+	if err := validateEvent(request.Type, request.Ratio); err != nil {
+		return operation.Operation{}, err
+	}
+
+	op := operation.Operation{
+		Type:      request.Type,
+		Symbol:    request.Symbol,
+		Date:      request.Date,
+		Ratio:     request.Ratio,
+		UnitValue: request.UnitValue,
+		Target:    request.Target,
+	}
+
+	if op.Type == operation.Conversion && op.Destination() == "" {
+		return operation.Operation{}, fmt.Errorf("cannot resolve the symbol %s converts into: use --to <symbol>", op.Symbol)
 	}
 
 	if err := uc.Repository.Create(ctx, op); err != nil {
@@ -91,64 +301,269 @@ func (uc BuyOperationUseCase) Execute(ctx context.Context, request BuyRequest) (
 	return op, nil
 }
 
-func (uc ListUseCase) Execute(ctx context.Context) (operation.List, error) {
-	return uc.Repository.List(ctx)
+func (uc ListUseCase) Execute(ctx context.Context, portfolio string) (operation.List, error) {
+	operations, err := uc.Repository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return operations.In(portfolio), nil
 }
 
-func (uc ImportUseCase) Execute(ctx context.Context, reader io.Reader) ([]operation.Operation, error) {
-	operations, err := csv.Import(reader, true, operation.ParseFromCSV)
+func (uc PositionHistoryUseCase) Execute(ctx context.Context, portfolio string, symbol stock.Symbol) (operation.History, error) {
+	operations, err := uc.Repository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := methods(ctx, uc.Portfolios)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, o := range operations {
-		if err := uc.Fetcher.Fetch(ctx, o.Symbol); err != nil {
-			return nil, err
+	history := operations.In(portfolio).Ledger(methods).History(symbol)
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no operations found for %s", symbol)
+	}
+
+	return history, nil
+}
+
+func (uc RealizedUseCase) Execute(ctx context.Context, portfolio string, from, to time.Time) (operation.Sales, error) {
+	operations, err := uc.Repository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := methods(ctx, uc.Portfolios)
+	if err != nil {
+		return nil, err
+	}
+
+	return operations.In(portfolio).Ledger(methods).Sales().Between(from, to), nil
+}
+
+func (uc ImportUseCase) Execute(ctx context.Context, reader io.Reader, name string, dryRun bool) (operation.Reconciliation, error) {
+	operations, lines, err := csv.ImportLines(reader, true, operation.ParseFromCSV)
+	if err != nil {
+		return operation.Reconciliation{}, err
+	}
+
+	return uc.importLines(ctx, operations, lines, name, dryRun)
+}
+
+func (uc ImportUseCase) ExecuteNotes(ctx context.Context, reader io.Reader, symbols map[string]stock.Symbol, name string,
+	dryRun bool) (operation.Reconciliation, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return operation.Reconciliation{}, err
+	}
+
+	text, err := sinacor.ExtractText(ctx, data)
+	if err != nil {
+		return operation.Reconciliation{}, err
+	}
+
+	notes, err := sinacor.Parse(text)
+	if err != nil {
+		return operation.Reconciliation{}, err
+	}
+
+	var operations operation.List
+	for _, note := range notes {
+		ops, err := note.Operations(symbols)
+		if err != nil {
+			return operation.Reconciliation{}, fmt.Errorf("note %s: %w", note.Number, err)
 		}
 
-		if err := uc.Repository.Create(ctx, o); err != nil {
-			return nil, err
+		operations = append(operations, ops...)
+	}
+
+	return uc.Import(ctx, operations, name, dryRun)
+}
+
+func (uc ImportUseCase) Import(ctx context.Context, operations operation.List, name string, dryRun bool) (operation.Reconciliation, error) {
+	return uc.importLines(ctx, operations, nil, name, dryRun)
+}
+
+func (uc ImportUseCase) importLines(ctx context.Context, operations operation.List, lines []int, name string,
+	dryRun bool) (operation.Reconciliation, error) {
+	for i, o := range operations {
+		if o.Portfolio == "" && !o.Type.IsEvent() {
+			operations[i].Portfolio = portfolio.OrDefault(name)
 		}
 	}
 
-	return operations, nil
+	existing, err := uc.Repository.List(ctx)
+	if err != nil {
+		return operation.Reconciliation{}, err
+	}
+
+	reconciliation := operations.Reconcile(existing)
+	if err := oversold(existing, reconciliation.Inserted, operations, lines); err != nil {
+		return operation.Reconciliation{}, err
+	}
+
+	if dryRun {
+		return reconciliation, nil
+	}
+
+	if err := uc.Fetcher.Fetch(ctx, fetchable(reconciliation.Inserted)...); err != nil {
+		return operation.Reconciliation{}, err
+	}
+
+	if err := uc.Repository.CreateAll(ctx, reconciliation.Inserted); err != nil {
+		return operation.Reconciliation{}, err
+	}
+
+	return reconciliation, nil
 }
 
-func (uc AssetsUseCase) Execute(ctx context.Context) (asset.Assets, error) {
-	assets, err := uc.Repository.Assets(ctx)
+func (uc AssetsUseCase) Execute(ctx context.Context, portfolio string) (asset.Assets, error) {
+	assets, err := uc.Repository.Assets(ctx, portfolio)
 	if err != nil {
 		return nil, err
 	}
 
-	done := make(chan bool)
-	fail := make(chan error)
+	var symbols []stock.Symbol
+	for _, a := range assets {
+		if a.Quantity != 0 {
+			symbols = append(symbols, a.Symbol)
+		}
+	}
+
+	if len(symbols) == 0 {
+		return assets, nil
+	}
+
+	infos, err := uc.Provider.LastInfos(ctx, symbols)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 
-	wg := sync.WaitGroup{}
+	failed := stock.Failed(err, symbols)
 	for i := range assets {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
-			info, err := uc.Provider.LastInfo(ctx, assets[i].Symbol)
-			if err != nil {
-				fail <- err
-				return
+		if assets[i].Quantity == 0 {
+			continue
+		}
+
+		info, ok := infos[assets[i].Symbol]
+		switch {
+		case ok:
+			assets[i].LastPrice = info.LastPrice
+			assets[i].Stale = info.Stale
+		case failed[assets[i].Symbol] != nil:
+			assets[i].QuoteError = failed[assets[i].Symbol]
+		default:
+			assets[i].QuoteError = fmt.Errorf("no quote available for %s", assets[i].Symbol)
+		}
+	}
+
+	return assets, nil
+}
+
+func oversold(existing, inserted, operations operation.List, lines []int) error {
+	var errs csv.Errors
+	for _, o := range oversells(existing, existing.With(inserted...)) {
+		err := oversellError(o)
+
+		i := culprit(operations, o)
+		if i < 0 || lines == nil {
+			return err
+		}
+
+		errs = append(errs, csv.LineError{Line: lines[i], Err: err})
+	}
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].Line < errs[j].Line
+		})
+
+		return errs
+	}
+
+	return nil
+}
+
+func oversells(before, after operation.List) []operation.Oversell {
+	var names []string
+	seen := map[string]bool{}
+	for _, op := range after {
+		if op.Portfolio != "" && !seen[op.Portfolio] {
+			seen[op.Portfolio] = true
+			names = append(names, op.Portfolio)
+		}
+	}
+	sort.Strings(names)
+
+	var output []operation.Oversell
+	for _, name := range names {
+		known := map[string]bool{}
+		for _, o := range before.In(name).Ledger(nil).Oversold() {
+			known[oversellKey(o)] = true
+		}
+
+		for _, o := range after.In(name).Ledger(nil).Oversold() {
+			if !known[oversellKey(o)] {
+				output = append(output, o)
 			}
+		}
+	}
+
+	return output
+}
+
+func oversellError(o operation.Oversell) error {
+	return fmt.Errorf("cannot sell %d %s on %s: position in %s is %d",
+		o.Quantity, o.Symbol, o.Date.Format("2006-01-02"), o.Portfolio, o.Held)
+}
+
+func oversellKey(o operation.Oversell) string {
+	return fmt.Sprintf("%s/%s/%s", o.Symbol, o.Date.Format("2006-01-02"), o.Portfolio)
+}
 
-			assets[i].LastPrice = currency.NewFromFloat(info.LastPrice)
-		}(i)
+func culprit(operations operation.List, o operation.Oversell) int {
+	n := -1
+	for i, op := range operations {
+		if op.Type != operation.Sell || op.Symbol != o.Symbol || op.Portfolio != o.Portfolio || op.Date.After(o.Date) {
+			continue
+		}
+
+		if n < 0 || op.Date.After(operations[n].Date) {
+			n = i
+		}
 	}
 
-	go func() {
-		wg.Wait()
-		done <- true
-	}()
+	return n
+}
 
-	select {
-	case <-done:
-		return assets, nil
-	case err := <-fail:
-		return nil, err
-	case <-ctx.Done():
-		return nil, ctx.Err()
+func validateEvent(t operation.Type, ratio float64) error {
+	switch {
+	case t == operation.Conversion && ratio <= 0:
+		return fmt.Errorf("invalid ratio for %s: %v", t, ratio)
+	case t == operation.Conversion:
+		return nil
+	case t == operation.ReverseSplit && (ratio <= 0 || ratio >= 1):
+		return fmt.Errorf("invalid ratio for %s: %v", t, ratio)
+	case t != operation.ReverseSplit && ratio <= 1:
+		return fmt.Errorf("invalid ratio for %s: %v", t, ratio)
+	default:
+		return nil
 	}
 }
+
+func fetchable(operations operation.List) []stock.Symbol {
+	symbols := make([]stock.Symbol, len(operations))
+
+	for i, o := range operations {
+		switch o.Type {
+		case operation.Right, operation.Subscription, operation.Conversion:
+			symbols[i] = o.Symbol.Underlying()
+		default:
+			symbols[i] = o.Symbol
+		}
+	}
+
+	return symbols
+}
@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"stocks/asset"
+	"stocks/currency"
+	"stocks/operation"
+	"stocks/portfolio"
+	"stocks/stock"
+	"testing"
+	"time"
+)
+
+type (
+	fakeRepository struct {
+		operations operation.List
+		created    operation.List
+	}
+
+	fakePortfolios portfolio.Portfolios
+
+	fakeFetcher struct {
+		fetched []stock.Symbol
+	}
+)
+
+func (r *fakeRepository) Create(_ context.Context, op operation.Operation) error {
+	r.created = append(r.created, op)
+	return nil
+}
+
+func (r *fakeRepository) CreateAll(_ context.Context, operations operation.List) error {
+	r.created = append(r.created, operations...)
+	return nil
+}
+
+func (r *fakeRepository) List(context.Context) (operation.List, error) {
+	return r.operations, nil
+}
+
+func (r *fakeRepository) Get(context.Context, uint) (operation.Operation, error) {
+	return operation.Operation{}, nil
+}
+
+func (r *fakeRepository) Update(context.Context, operation.Operation) error {
+	return nil
+}
+
+func (r *fakeRepository) Delete(context.Context, uint) error {
+	return nil
+}
+
+func (r *fakeRepository) Changes(context.Context) (operation.Changes, error) {
+	return nil, nil
+}
+
+func (p fakePortfolios) CreatePortfolio(context.Context, portfolio.Portfolio) error {
+	return nil
+}
+
+func (p fakePortfolios) Portfolios(context.Context) (portfolio.Portfolios, error) {
+	return portfolio.Portfolios(p), nil
+}
+
+func (p fakePortfolios) UpdatePortfolio(context.Context, portfolio.Portfolio) error {
+	return nil
+}
+
+func (f *fakeFetcher) Fetch(_ context.Context, symbols ...stock.Symbol) error {
+	f.fetched = append(f.fetched, symbols...)
+	return nil
+}
+
+func TestBuyOperationUseCase_Execute(t *testing.T) {
+	date := time.Date(2022, 4, 25, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		request BuyRequest
+		want    operation.Operation
+		wantErr bool
+	}{
+		{
+			name:    "Should record buys in the default portfolio",
+			request: BuyRequest{Symbol: "STOCK1", Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: date},
+			want: operation.Operation{Type: operation.Buy, Symbol: "STOCK1", Quantity: 10, UnitValue: currency.NewFromFloat(10),
+				Date: date, Portfolio: portfolio.Default},
+		},
+		{
+			name:    "Should reject buys without a positive quantity",
+			request: BuyRequest{Symbol: "STOCK1", Quantity: 0, UnitValue: currency.NewFromFloat(10), Date: date},
+			wantErr: true,
+		},
+		{
+			name:    "Should reject buys with a negative value",
+			request: BuyRequest{Symbol: "STOCK1", Quantity: 10, UnitValue: currency.NewFromFloat(-10), Date: date},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeRepository{}
+			fetcher := &fakeFetcher{}
+
+			got, err := NewBuyOperationUseCase(repository, fetcher).Execute(context.Background(), tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && (len(repository.created) > 0 || len(fetcher.fetched) > 0) {
+				t.Errorf("Execute() created %v and fetched %v for an invalid buy", repository.created, fetcher.fetched)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Execute() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSellOperationUseCase_Execute(t *testing.T) {
+	day1 := time.Date(2022, 4, 25, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2022, 4, 26, 0, 0, 0, 0, time.UTC)
+	day3 := time.Date(2022, 4, 27, 0, 0, 0, 0, time.UTC)
+
+	operations := operation.List{
+		{Symbol: "STOCK1", Type: operation.Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: portfolio.Default},
+		{Symbol: "STOCK1", Type: operation.Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day2, Portfolio: portfolio.Default},
+		{Symbol: "STOCK1", Type: operation.Buy, Quantity: 10, UnitValue: currency.NewFromFloat(10), Date: day1, Portfolio: "us"},
+		{Symbol: "STOCK1", Type: operation.Buy, Quantity: 10, UnitValue: currency.NewFromFloat(20), Date: day2, Portfolio: "us"},
+	}
+	portfolios := fakePortfolios{
+		{Name: portfolio.Default},
+		{Name: "us", Method: operation.SpecificLot},
+	}
+
+	tests := []struct {
+		name     string
+		request  SellRequest
+		wantGain currency.Currency
+		wantErr  bool
+	}{
+		{
+			name:     "Should record sells and report the gain with the portfolio method",
+			request:  SellRequest{Symbol: "STOCK1", Quantity: 10, UnitValue: currency.NewFromFloat(30), Date: day3},
+			wantGain: currency.NewFromFloat(150),
+		},
+		{
+			name:    "Should reject sells above the current position",
+			request: SellRequest{Symbol: "STOCK1", Quantity: 21, UnitValue: currency.NewFromFloat(30), Date: day3},
+			wantErr: true,
+		},
+		{
+			name:    "Should reject sells above the position at the sell date",
+			request: SellRequest{Symbol: "STOCK1", Quantity: 15, UnitValue: currency.NewFromFloat(30), Date: day1},
+			wantErr: true,
+		},
+		{
+			name:    "Should reject sells without a positive quantity",
+			request: SellRequest{Symbol: "STOCK1", Quantity: -5, UnitValue: currency.NewFromFloat(30), Date: day3},
+			wantErr: true,
+		},
+		{
+			name:    "Should reject sells with a negative value",
+			request: SellRequest{Symbol: "STOCK1", Quantity: 5, UnitValue: currency.NewFromFloat(-30), Date: day3},
+			wantErr: true,
+		},
+		{
+			name:    "Should reject lots on average cost portfolios",
+			request: SellRequest{Symbol: "STOCK1", Quantity: 5, UnitValue: currency.NewFromFloat(30), Date: day3, Lot: day1},
+			wantErr: true,
+		},
+		{
+			name:     "Should sell the identified lot on specific lot portfolios",
+			request:  SellRequest{Symbol: "STOCK1", Quantity: 10, UnitValue: currency.NewFromFloat(30), Date: day3, Portfolio: "us", Lot: day2},
+			wantGain: currency.NewFromFloat(100),
+		},
+		{
+			name:    "Should reject specific lot sells without a lot",
+			request: SellRequest{Symbol: "STOCK1", Quantity: 5, UnitValue: currency.NewFromFloat(30), Date: day3, Portfolio: "us"},
+			wantErr: true,
+		},
+		{
+			name:    "Should reject specific lot sells above the identified lot",
+			request: SellRequest{Symbol: "STOCK1", Quantity: 15, UnitValue: currency.NewFromFloat(30), Date: day3, Portfolio: "us", Lot: day2},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeRepository{operations: operations}
+
+			got, err := NewSellOperationUseCase(repository, portfolios).Execute(context.Background(), tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if len(repository.created) > 0 {
+					t.Errorf("Execute() created %v for a rejected sell", repository.created)
+				}
+				return
+			}
+			if len(repository.created) != 1 || repository.created[0] != got.Operation {
+				t.Errorf("Execute() created = %v, want %v", repository.created, got.Operation)
+			}
+			if got.Gain != tt.wantGain {
+				t.Errorf("Execute() gain = %v, want %v", got.Gain, tt.wantGain)
+			}
+		})
+	}
+}
+
+type (
+	fakeAssets asset.Assets
+
+	fakeProvider struct {
+		infos     map[stock.Symbol]stock.Info
+		requested []stock.Symbol
+	}
+)
+
+func (a fakeAssets) Assets(context.Context, string) (asset.Assets, error) {
+	return append(asset.Assets{}, a...), nil
+}
+
+func (a fakeAssets) AssetsAt(context.Context, string, time.Time) (asset.Assets, error) {
+	return append(asset.Assets{}, a...), nil
+}
+
+func (p *fakeProvider) Details(context.Context, stock.Symbol) (stock.Details, error) {
+	return stock.Details{}, nil
+}
+
+func (p *fakeProvider) LastInfo(_ context.Context, symbol stock.Symbol) (stock.Info, error) {
+	return p.infos[symbol], nil
+}
+
+func (p *fakeProvider) LastInfos(_ context.Context, symbols []stock.Symbol) (map[stock.Symbol]stock.Info, error) {
+	p.requested = append(p.requested, symbols...)
+
+	infos := map[stock.Symbol]stock.Info{}
+	for _, symbol := range symbols {
+		if info, ok := p.infos[symbol]; ok {
+			infos[symbol] = info
+		}
+	}
+
+	return infos, nil
+}
+
+func (p *fakeProvider) History(context.Context, stock.Symbol, time.Time, time.Time) (stock.Candles, error) {
+	return nil, nil
+}
+
+func TestAssetsUseCase_Execute(t *testing.T) {
+	assets := fakeAssets{
+		{Symbol: "STOCK1", Quantity: 10, Cost: currency.NewFromFloat(100)},
+		{Symbol: "STOCK2", Realized: currency.NewFromFloat(20), Income: currency.NewFromFloat(5)},
+		{Symbol: "STOCK3", Quantity: 5, Cost: currency.NewFromFloat(50)},
+	}
+	provider := &fakeProvider{
+		infos: map[stock.Symbol]stock.Info{"STOCK1": {Symbol: "STOCK1", LastPrice: currency.NewFromFloat(12)}},
+	}
+
+	got, err := NewAssetsUseCase(provider, assets).Execute(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if want := []stock.Symbol{"STOCK1", "STOCK3"}; !reflect.DeepEqual(provider.requested, want) {
+		t.Errorf("Execute() requested = %v, want %v", provider.requested, want)
+	}
+
+	if !got[1].Priced() || got[2].Priced() {
+		t.Errorf("Execute() priced = %v, %v, want true, false", got[1].Priced(), got[2].Priced())
+	}
+
+	if want := currency.NewFromFloat(45); got.GainLoss() != want {
+		t.Errorf("Execute() gain / loss = %v, want %v", got.GainLoss(), want)
+	}
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"stocks/operation"
+	"stocks/portfolio"
+)
+
+type (
+	CreatePortfolioUseCase struct {
+		Repository portfolio.Repository
+	}
+
+	ListPortfoliosUseCase struct {
+		Repository portfolio.Repository
+	}
+
+	SetMethodUseCase struct {
+		Repository portfolio.Repository
+	}
+)
+
+func NewCreatePortfolioUseCase(repository portfolio.Repository) *CreatePortfolioUseCase {
+	return &CreatePortfolioUseCase{
+		Repository: repository,
+	}
+}
+
+func NewListPortfoliosUseCase(repository portfolio.Repository) *ListPortfoliosUseCase {
+	return &ListPortfoliosUseCase{
+		Repository: repository,
+	}
+}
+
+func NewSetMethodUseCase(repository portfolio.Repository) *SetMethodUseCase {
+	return &SetMethodUseCase{
+		Repository: repository,
+	}
+}
+
+func (uc CreatePortfolioUseCase) Execute(ctx context.Context, request portfolio.Portfolio) (portfolio.Portfolio, error) {
+	if request.Name == "" {
+		return portfolio.Portfolio{}, errors.New("portfolio name is required")
+	}
+
+	if err := uc.Repository.CreatePortfolio(ctx, request); err != nil {
+		return portfolio.Portfolio{}, err
+	}
+
+	return request, nil
+}
+
+func (uc ListPortfoliosUseCase) Execute(ctx context.Context) (portfolio.Portfolios, error) {
+	return uc.Repository.Portfolios(ctx)
+}
+
+func (uc SetMethodUseCase) Execute(ctx context.Context, name string, method operation.Method) (portfolio.Portfolio, error) {
+	portfolios, err := uc.Repository.Portfolios(ctx)
+	if err != nil {
+		return portfolio.Portfolio{}, err
+	}
+
+	for _, p := range portfolios {
+		if p.Name != name {
+			continue
+		}
+
+		p.Method = method
+		if err := uc.Repository.UpdatePortfolio(ctx, p); err != nil {
+			return portfolio.Portfolio{}, err
+		}
+
+		return p, nil
+	}
+
+	return portfolio.Portfolio{}, fmt.Errorf("portfolio %s not found", name)
+}
+
+func methods(ctx context.Context, repository portfolio.Repository) (operation.Methods, error) {
+	portfolios, err := repository.Portfolios(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return portfolios.Methods(), nil
+}
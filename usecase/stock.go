@@ -9,6 +9,11 @@ type (
 	GetLastPrice struct {
 		Integration stock.Provider
 	}
+
+	SetCNPJUseCase struct {
+		Repository stock.Repository
+		Fetcher    Fetcher
+	}
 )
 
 func NewGetLastPrice(stockService stock.Provider) *GetLastPrice {
@@ -17,11 +22,31 @@ func NewGetLastPrice(stockService stock.Provider) *GetLastPrice {
 	}
 }
 
-func (uc GetLastPrice) Execute(ctx context.Context, symbol stock.Symbol) (float64, error) {
-	info, err := uc.Integration.LastInfo(ctx, symbol)
+func (uc GetLastPrice) Execute(ctx context.Context, symbol stock.Symbol) (stock.Info, error) {
+	return uc.Integration.LastInfo(ctx, symbol)
+}
+
+func NewSetCNPJUseCase(repository stock.Repository, fetcher Fetcher) *SetCNPJUseCase {
+	return &SetCNPJUseCase{
+		Repository: repository,
+		Fetcher:    fetcher,
+	}
+}
+
+func (uc SetCNPJUseCase) Execute(ctx context.Context, symbol stock.Symbol, cnpj string) (stock.Details, error) {
+	if err := uc.Fetcher.Fetch(ctx, symbol); err != nil {
+		return stock.Details{}, err
+	}
+
+	details, err := uc.Repository.GetDetails(ctx, symbol)
 	if err != nil {
-		return 0, err
+		return stock.Details{}, err
+	}
+
+	details.CNPJ = cnpj
+	if err := uc.Repository.UpdateDetails(ctx, details); err != nil {
+		return stock.Details{}, err
 	}
 
-	return info.LastPrice, err
+	return details, nil
 }
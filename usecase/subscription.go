@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"stocks/currency"
+	"stocks/operation"
+	"stocks/portfolio"
+	"stocks/stock"
+	"time"
+)
+
+type (
+	RightsRequest struct {
+		Symbol    stock.Symbol
+		Quantity  int
+		Date      time.Time
+		Portfolio string
+	}
+
+	SubscribeRequest struct {
+		Symbol    stock.Symbol
+		Quantity  int
+		UnitValue currency.Currency
+		Fees      operation.Fees
+		Date      time.Time
+		Portfolio string
+		Target    stock.Symbol
+	}
+
+	RightsUseCase struct {
+		Repository operation.Repository
+	}
+
+	SubscribeUseCase struct {
+		Repository operation.Repository
+	}
+)
+
+func NewRightsUseCase(repository operation.Repository) *RightsUseCase {
+	return &RightsUseCase{
+		Repository: repository,
+	}
+}
+
+func NewSubscribeUseCase(repository operation.Repository) *SubscribeUseCase {
+	return &SubscribeUseCase{
+		Repository: repository,
+	}
+}
+
+func (uc RightsUseCase) Execute(ctx context.Context, request RightsRequest) (operation.Operation, error) {
+	if request.Quantity <= 0 {
+		return operation.Operation{}, errors.New("quantity of rights must be positive")
+	}
+
+	op := operation.Operation{
+		Type:      operation.Right,
+		Symbol:    request.Symbol,
+		Date:      request.Date,
+		Quantity:  request.Quantity,
+		Portfolio: portfolio.OrDefault(request.Portfolio),
+	}
+
+	if err := uc.Repository.Create(ctx, op); err != nil {
+		return operation.Operation{}, err
+	}
+
+	return op, nil
+}
+
+func (uc SubscribeUseCase) Execute(ctx context.Context, request SubscribeRequest) (operation.Operation, error) {
+	op := operation.Operation{
+		Type:      operation.Subscription,
+		Symbol:    request.Symbol,
+		Date:      request.Date,
+		Quantity:  request.Quantity,
+		UnitValue: request.UnitValue,
+		Fees:      request.Fees,
+		Portfolio: portfolio.OrDefault(request.Portfolio),
+		Target:    request.Target,
+	}
+
+	if op.Destination() == "" {
+		return operation.Operation{}, fmt.Errorf("cannot resolve the receipt of %s: use --to <symbol>", op.Symbol)
+	}
+
+	operations, err := uc.Repository.List(ctx)
+	if err != nil {
+		return operation.Operation{}, err
+	}
+
+	held := operations.In(op.Portfolio).Until(op.Date).Position(op.Symbol).Quantity
+	if op.Quantity <= 0 || op.Quantity > held {
+		return operation.Operation{}, fmt.Errorf("cannot exercise %d %s: current rights in %s are %d",
+			op.Quantity, op.Symbol, op.Portfolio, held)
+	}
+
+	if err := uc.Repository.Create(ctx, op); err != nil {
+		return operation.Operation{}, err
+	}
+
+	return op, nil
+}
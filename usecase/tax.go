@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"stocks/income"
+	"stocks/irpf"
+	"stocks/operation"
+	"stocks/stock"
+	"stocks/tax"
+	"time"
+)
+
+type (
+	TaxUseCase struct {
+		Repository operation.Repository
+		Details    stock.Repository
+	}
+
+	IRPFUseCase struct {
+		Repository operation.Repository
+		Incomes    income.Repository
+		Details    stock.Repository
+	}
+)
+
+func NewTaxUseCase(repository operation.Repository, details stock.Repository) *TaxUseCase {
+	return &TaxUseCase{
+		Repository: repository,
+		Details:    details,
+	}
+}
+
+func (uc TaxUseCase) Execute(ctx context.Context, period time.Time) (tax.Report, error) {
+	operations, err := uc.Repository.List(ctx)
+	if err != nil {
+		return tax.Report{}, err
+	}
+
+	var symbols []stock.Symbol
+	for _, op := range operations {
+		symbols = append(symbols, op.Symbol)
+	}
+
+	classes := map[stock.Symbol]stock.Class{}
+	for symbol, d := range details(ctx, uc.Details, symbols) {
+		classes[symbol] = d.Class
+	}
+
+	return tax.Calculate(operations, classes, period), nil
+}
+
+func NewIRPFUseCase(repository operation.Repository, incomes income.Repository, details stock.Repository) *IRPFUseCase {
+	return &IRPFUseCase{
+		Repository: repository,
+		Incomes:    incomes,
+		Details:    details,
+	}
+}
+
+func (uc IRPFUseCase) Execute(ctx context.Context, year int) (irpf.Report, error) {
+	operations, err := uc.Repository.List(ctx)
+	if err != nil {
+		return irpf.Report{}, err
+	}
+
+	incomes, err := uc.Incomes.Incomes(ctx)
+	if err != nil {
+		return irpf.Report{}, err
+	}
+
+	var symbols []stock.Symbol
+	for _, op := range operations {
+		symbols = append(symbols, op.Symbol)
+	}
+	for _, i := range incomes {
+		symbols = append(symbols, i.Symbol)
+	}
+
+	return irpf.Calculate(operations, incomes, details(ctx, uc.Details, symbols), year), nil
+}
+
+func details(ctx context.Context, repository stock.Repository, symbols []stock.Symbol) map[stock.Symbol]stock.Details {
+	output := map[stock.Symbol]stock.Details{}
+	for _, symbol := range symbols {
+		if _, ok := output[symbol]; ok {
+			continue
+		}
+
+		if d, err := repository.GetDetails(ctx, symbol); err == nil {
+			output[symbol] = d
+		}
+	}
+
+	return output
+}